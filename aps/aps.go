@@ -0,0 +1,162 @@
+// Package aps reads the property database of models translated by Autodesk Platform Services (APS, formerly Forge),
+// and maps the externalId of every object (dbId) to an IFC GUID.
+//
+// The property database is downloaded alongside the SVF/SVF2 derivative as a set of gzip-compressed JSON files.
+// This package only needs objects_ids.json.gz, which lists the externalId of every dbId.
+// For Revit sources, the externalId is the Revit UniqueId; for AutoCAD sources, it is the hexadecimal handle.
+// When the source file of the model is known, pass its scheme to LoadTableFor, see SourceScheme:
+// short handles are ambiguous on their own.
+//
+// Usage:
+//
+//	table, err := aps.LoadTableFor("path/to/propertydb", aps.SourceScheme("tower.rvt"))
+//	for _, e := range table {
+//		fmt.Println(e.DbId, e.ExternalId, e.GlobalId)
+//	}
+package aps
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/woweh/ifcguid"
+)
+
+// ObjectIdsFile is the name of the property database file that holds the externalIds.
+const ObjectIdsFile = "objects_ids.json.gz"
+
+// Entry maps one dbId of a translated model to its externalId and IFC GUID.
+type Entry struct {
+	// DbId is the viewer's object id, i.e. the index into objects_ids.
+	DbId int
+	// ExternalId is the id of the object in the source document, e.g. a Revit UniqueId.
+	ExternalId string
+	// GlobalId is the IFC GUID derived from ExternalId, or empty if Err is set.
+	GlobalId string
+	// Scheme is the name of the identifier scheme used to convert ExternalId, see ifcguid.IdScheme.
+	Scheme string
+	// Err is set if ExternalId couldn't be converted to an IFC GUID.
+	Err error
+}
+
+// ReadExternalIds reads the externalIds from the content of objects_ids.json(.gz).
+// Gzip compression is detected automatically. The index of each element is its dbId.
+func ReadExternalIds(r io.Reader) ([]string, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	var src io.Reader = br
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		src = gz
+	}
+	// objects_ids is an array of strings, except that the first element (dbId 0) may be a number or null.
+	var raw []any
+	if err := json.NewDecoder(src).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", ObjectIdsFile, err)
+	}
+	ids := make([]string, len(raw))
+	for i, v := range raw {
+		if s, ok := v.(string); ok {
+			ids[i] = s
+		}
+	}
+	return ids, nil
+}
+
+// _sourceSchemes are the schemes of the externalIds by source file extension.
+var _sourceSchemes = map[string]ifcguid.IdScheme{
+	".rvt":    ifcguid.SchemeRevit,
+	".rfa":    ifcguid.SchemeRevit,
+	".rte":    ifcguid.SchemeRevit,
+	".dwg":    ifcguid.SchemeAutoCad,
+	".dxf":    ifcguid.SchemeAutoCad,
+	".ifc":    ifcguid.SchemeIfcGuid,
+	".ifczip": ifcguid.SchemeIfcGuid,
+	".ifcxml": ifcguid.SchemeIfcGuid,
+}
+
+// SourceScheme returns the scheme of the externalIds of a model translated from the source file with the given name,
+// e.g. "tower.rvt", by its extension. It returns nil for other formats, whose scheme BuildTableFor detects.
+func SourceScheme(sourceFile string) ifcguid.IdScheme {
+	return _sourceSchemes[strings.ToLower(filepath.Ext(sourceFile))]
+}
+
+// BuildTable converts the externalIds of a property database to IFC GUIDs, detecting the scheme of each one.
+// It is BuildTableFor with a nil scheme.
+func BuildTable(externalIds []string) []Entry {
+	return BuildTableFor(externalIds, nil)
+}
+
+// BuildTableFor converts the externalIds of a property database to IFC GUIDs with the given scheme,
+// e.g. the one SourceScheme returns for the source file of the model. dbIds without an externalId are skipped.
+//
+// If scheme is nil, Revit UniqueIds are converted with ifcguid.FromRevitUniqueId,
+// and other externalIds with the scheme ifcguid.Identify finds. Short AutoCAD handles and integers
+// are ambiguous on their own, and get an error then.
+func BuildTableFor(externalIds []string, scheme ifcguid.IdScheme) []Entry {
+	table := make([]Entry, 0, len(externalIds))
+	for dbId, externalId := range externalIds {
+		if externalId == "" {
+			continue
+		}
+		table = append(table, convert(dbId, externalId, scheme))
+	}
+	return table
+}
+
+// LoadTable reads objects_ids.json.gz from the property database directory dir and converts it with BuildTable.
+func LoadTable(dir string) ([]Entry, error) {
+	return LoadTableFor(dir, nil)
+}
+
+// LoadTableFor reads objects_ids.json.gz from the property database directory dir
+// and converts it with BuildTableFor.
+func LoadTableFor(dir string, scheme ifcguid.IdScheme) ([]Entry, error) {
+	f, err := os.Open(filepath.Join(dir, ObjectIdsFile))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	ids, err := ReadExternalIds(f)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTableFor(ids, scheme), nil
+}
+
+// convert converts a single externalId with the given scheme, or the detected one if it is nil.
+func convert(dbId int, externalId string, scheme ifcguid.IdScheme) Entry {
+	e := Entry{DbId: dbId, ExternalId: externalId}
+	if scheme == nil && ifcguid.IsValidRevitUniqueId(externalId) {
+		scheme = ifcguid.SchemeRevit
+	}
+	if scheme == nil {
+		i, err := ifcguid.Identify(externalId)
+		if err != nil {
+			e.Err = err
+			return e
+		}
+		e.Scheme, e.GlobalId = i.Scheme.Name(), i.GlobalId
+		return e
+	}
+	if !scheme.Detect(externalId) {
+		e.Err = fmt.Errorf("the %s scheme doesn't accept %q", scheme.Name(), externalId)
+		return e
+	}
+	e.Scheme = scheme.Name()
+	e.GlobalId, e.Err = ifcguid.Convert(scheme, ifcguid.SchemeIfcGuid, externalId)
+	return e
+}
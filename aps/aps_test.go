@@ -0,0 +1,115 @@
+package aps
+
+import (
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/woweh/ifcguid"
+)
+
+const objectIds = `[0,
+"8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e",
+"",
+"2DWKyvjkf7PffFYiFUDNsy",
+"3085A8E4-61FD-4776-9FF1-1B24A646CA4F",
+"doc_9a3e/123"]`
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	_, err := gz.Write([]byte(s))
+	assert.NoError(t, err)
+	assert.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func Test_ReadExternalIds(t *testing.T) {
+	for name, content := range map[string][]byte{
+		"gzip":  gzipped(t, objectIds),
+		"plain": []byte(objectIds),
+	} {
+		t.Run(name, func(t *testing.T) {
+			ids, err := ReadExternalIds(bytes.NewReader(content))
+			assert.NoError(t, err)
+			assert.Len(t, ids, 6)
+			assert.Empty(t, ids[0])
+			assert.Equal(t, "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e", ids[1])
+		})
+	}
+
+	_, err := ReadExternalIds(strings.NewReader(`{"not": "an array"}`))
+	assert.Error(t, err)
+}
+
+func Test_LoadTable(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, ObjectIdsFile), gzipped(t, objectIds), 0o644))
+
+	table, err := LoadTable(dir)
+	assert.NoError(t, err)
+	if !assert.Len(t, table, 4) {
+		return
+	}
+
+	assert.Equal(t, 1, table[0].DbId)
+	assert.Equal(t, ifcguid.SchemeNameRevit, table[0].Scheme)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", table[0].GlobalId)
+	assert.NoError(t, table[0].Err)
+
+	assert.Equal(t, 3, table[1].DbId)
+	assert.Equal(t, ifcguid.SchemeNameIfcGuid, table[1].Scheme)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", table[1].GlobalId)
+
+	assert.Equal(t, 4, table[2].DbId)
+	assert.Equal(t, "0mXQZaOVr7Tf$n6oIcHifF", table[2].GlobalId)
+
+	assert.Equal(t, 5, table[3].DbId)
+	assert.Error(t, table[3].Err)
+	assert.Empty(t, table[3].GlobalId)
+
+	_, err = LoadTable(t.TempDir())
+	assert.Error(t, err)
+}
+
+func Test_SourceScheme(t *testing.T) {
+	assert.Equal(t, ifcguid.SchemeRevit, SourceScheme("Tower.RVT"))
+	assert.Equal(t, ifcguid.SchemeAutoCad, SourceScheme("site plan.dwg"))
+	assert.Equal(t, ifcguid.SchemeIfcGuid, SourceScheme("model.ifc"))
+	assert.Nil(t, SourceScheme("model.nwd"))
+	assert.Nil(t, SourceScheme("dwg"))
+}
+
+func Test_BuildTableFor_AutoCAD(t *testing.T) {
+	// dbId 0 is the root; the externalIds of AutoCAD sources are entity handles, short hexadecimal numbers.
+	externalIds := []string{"", "2F4", "1A3B", "Layer0"}
+
+	table := BuildTableFor(externalIds, SourceScheme("site plan.dwg"))
+	if assert.Len(t, table, 3) {
+		assert.Equal(t, ifcguid.SchemeNameAutoCad, table[0].Scheme)
+		want, err := ifcguid.FromAutoCadHandle("2F4")
+		assert.NoError(t, err)
+		assert.Equal(t, want, table[0].GlobalId)
+		assert.NoError(t, table[1].Err)
+		assert.EqualError(t, table[2].Err, `the autocad scheme doesn't accept "Layer0"`)
+	}
+
+	table = BuildTable(externalIds)
+	if assert.Len(t, table, 3) {
+		assert.ErrorContains(t, table[0].Err, "ambiguous identifier", "without the source, handles are ambiguous")
+		assert.Empty(t, table[0].GlobalId)
+	}
+}
+
+func Test_BuildTableFor_Revit(t *testing.T) {
+	table := BuildTableFor([]string{"", "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e"}, ifcguid.SchemeRevit)
+	if assert.Len(t, table, 1) {
+		assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", table[0].GlobalId)
+		assert.Equal(t, ifcguid.SchemeNameRevit, table[0].Scheme)
+	}
+}
@@ -0,0 +1,76 @@
+package ifcguid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// SchemeNameArchicad is the name of the built-in Archicad GUID scheme.
+const SchemeNameArchicad = "archicad"
+
+// SchemeArchicad handles Archicad element GUIDs, see FromArchicadGuid.
+var SchemeArchicad IdScheme = archicadScheme{}
+
+// _archicadGuid matches an Archicad GUID in the 8-4-4-4-12 form.
+var _archicadGuid = regexp.MustCompile(`^[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`)
+
+func init() {
+	mustRegisterScheme(SchemeArchicad)
+}
+
+// IsValidArchicadGuid checks if a string is an Archicad element GUID.
+//
+// Archicad writes element GUIDs in element schedules and API exports in the 8-4-4-4-12 form, in upper case,
+// without braces. Lower case input is accepted as well.
+func IsValidArchicadGuid(archicadGuid string) bool {
+	return len(archicadGuid) == 36 && _archicadGuid.MatchString(archicadGuid)
+}
+
+// FromArchicadGuid converts an Archicad element GUID to an IFC GUID.
+//
+// Archicad's IFC export compresses the GUID in the order of its text representation,
+// i.e. the same byte order as FromUuidString, and not the raw in-memory order of the Windows GUID struct.
+func FromArchicadGuid(archicadGuid string) (string, error) {
+	u, err := archicadGuidToUuid(archicadGuid)
+	if err != nil {
+		return "", err
+	}
+	return FromUuid(u)
+}
+
+// ToArchicadGuid converts an IFC GUID to an Archicad element GUID (upper case 8-4-4-4-12 form).
+func ToArchicadGuid(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	return uuidToArchicadGuid(u), nil
+}
+
+// archicadGuidToUuid converts an Archicad GUID to a UUID.
+func archicadGuidToUuid(archicadGuid string) (uuid.UUID, error) {
+	if !IsValidArchicadGuid(archicadGuid) {
+		return uuid.Nil, fmt.Errorf("the given string isn't an Archicad GUID: %v", archicadGuid)
+	}
+	return uuid.Parse(archicadGuid)
+}
+
+// uuidToArchicadGuid converts a UUID to an Archicad GUID.
+func uuidToArchicadGuid(u uuid.UUID) string {
+	return strings.ToUpper(u.String())
+}
+
+// archicadScheme implements IdScheme for Archicad GUIDs.
+type archicadScheme struct{}
+
+func (archicadScheme) Name() string                           { return SchemeNameArchicad }
+func (archicadScheme) Detect(value string) bool               { return IsValidArchicadGuid(value) }
+func (archicadScheme) ToUuid(value string) (uuid.UUID, error) { return archicadGuidToUuid(value) }
+func (archicadScheme) FromUuid(u uuid.UUID) (string, error)   { return uuidToArchicadGuid(u), nil }
+func (archicadScheme) Reversible() bool                       { return true }
+
+// Confidence is lower than for SchemeUuid: an Archicad GUID is indistinguishable from a plain GUID.
+func (archicadScheme) Confidence(string) float64 { return 0.9 }
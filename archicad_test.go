@@ -0,0 +1,81 @@
+package ifcguid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ArchicadGuid_conversions(t *testing.T) {
+	tests := []struct {
+		name         string
+		archicadGuid string
+		ifcGuid      string
+		wantError    bool
+	}{
+		{
+			name:         "Upper case GUID",
+			archicadGuid: "3085A8E4-61FD-4776-9FF1-1B24A646CA4F",
+			ifcGuid:      "0mXQZaOVr7Tf$n6oIcHifF",
+		},
+		{
+			name:         "GUID with leading zeros",
+			archicadGuid: "00C279B5-E1E6-476E-B087-A9777B77253E",
+			ifcGuid:      "00mdcruUP7Rh27gNTxToK_",
+		},
+		{
+			name:         "Lower case GUID",
+			archicadGuid: "e862ffb0-6793-4340-ad5e-62d18a70f9e3",
+			ifcGuid:      "3eOl_mPvD3GArUOj6ASFdZ",
+		},
+		{
+			name:         "Braced GUID",
+			archicadGuid: "{3085A8E4-61FD-4776-9FF1-1B24A646CA4F}",
+			wantError:    true,
+		},
+		{
+			name:         "GUID without hyphens",
+			archicadGuid: "3085A8E461FD47769FF11B24A646CA4F",
+			wantError:    true,
+		},
+		{
+			name:         "Empty string",
+			archicadGuid: "",
+			wantError:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIfcGuid, err := FromArchicadGuid(tt.archicadGuid)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.False(t, IsValidArchicadGuid(tt.archicadGuid))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ifcGuid, gotIfcGuid)
+
+			gotArchicadGuid, err := ToArchicadGuid(gotIfcGuid)
+			assert.NoError(t, err)
+			assert.Equal(t, strings.ToUpper(tt.archicadGuid), gotArchicadGuid)
+		})
+	}
+}
+
+func Test_ArchicadGuid_byte_order(t *testing.T) {
+	// 3085A8E4-61FD-4776-9FF1-1B24A646CA4F compressed in the order of its text representation,
+	// and in the little-endian order of the Windows GUID struct, which reverses the bytes of the first three groups.
+	const textOrder = "0mXQZaOVr7Tf$n6oIcHifF"
+	const structOrder = "3ag8Km$M5sHv$n6oIcHifF"
+
+	got, err := FromArchicadGuid("3085A8E4-61FD-4776-9FF1-1B24A646CA4F")
+	assert.NoError(t, err)
+	assert.Equal(t, textOrder, got)
+	assert.NotEqual(t, structOrder, got)
+
+	back, err := ToArchicadGuid(structOrder)
+	assert.NoError(t, err)
+	assert.Equal(t, "E4A88530-FD61-7647-9FF1-1B24A646CA4F", back)
+}
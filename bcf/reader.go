@@ -0,0 +1,395 @@
+// Package bcf reads and writes BIM Collaboration Format (BCF 2.1 and 3.0) archives,
+// with a focus on the IFC GUIDs that viewpoints use to reference model elements.
+//
+// Viewpoints (*.bcfv) reference elements through Component elements:
+//
+//	<Component IfcGuid="2DWKyvjkf7PffFYiFUDNsy">
+//	  <OriginatingSystem>Autodesk Revit</OriginatingSystem>
+//	  <AuthoringToolId>8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e</AuthoringToolId>
+//	</Component>
+//
+// Check validates every IfcGuid with ifcguid.IsValid and reports broken references.
+// Backfill additionally fills in missing IfcGuids by converting the AuthoringToolId.
+package bcf
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/internal/atomicfile"
+)
+
+// FindingKind classifies a problem with a component reference.
+type FindingKind int
+
+const (
+	// InvalidIfcGuid means the IfcGuid attribute is set, but isn't a valid IFC GUID.
+	InvalidIfcGuid FindingKind = iota
+	// MissingIfcGuid means the IfcGuid attribute is missing or empty.
+	// If the AuthoringToolId could be converted, Finding.GlobalId holds the suggested IFC GUID.
+	MissingIfcGuid
+	// FilledIfcGuid means the missing IfcGuid was filled in by Backfill.
+	FilledIfcGuid
+)
+
+// String returns a short description of the finding kind.
+func (k FindingKind) String() string {
+	switch k {
+	case InvalidIfcGuid:
+		return "invalid IfcGuid"
+	case MissingIfcGuid:
+		return "missing IfcGuid"
+	case FilledIfcGuid:
+		return "filled IfcGuid"
+	default:
+		return "FindingKind(" + strconv.Itoa(int(k)) + ")"
+	}
+}
+
+// Component is a reference to a model element in a viewpoint.
+type Component struct {
+	// File is the path of the viewpoint file in the archive, e.g. "<topic guid>/viewpoint.bcfv".
+	File string
+	// Line is the line of the Component element in File.
+	Line int
+	// IfcGuid is the value of the IfcGuid attribute.
+	IfcGuid string
+	// OriginatingSystem is the name of the system that created the element, e.g. "Autodesk Revit".
+	OriginatingSystem string
+	// AuthoringToolId is the id of the element in the originating system.
+	AuthoringToolId string
+}
+
+// Topic returns the guid of the topic the component belongs to, i.e. the folder of its viewpoint file.
+func (c Component) Topic() string {
+	return path.Dir(c.File)
+}
+
+// Finding is a problem with a component reference, or a fix applied to it.
+type Finding struct {
+	Component
+	Kind FindingKind
+	// GlobalId is the IFC GUID converted from the AuthoringToolId, if any.
+	GlobalId string
+	// Err explains why the IfcGuid is invalid, or why the AuthoringToolId couldn't be converted.
+	Err error
+}
+
+// Report is the result of checking a BCF archive.
+type Report struct {
+	// Components is the number of component references in all viewpoints.
+	Components int
+	// Findings lists every invalid, missing or filled IfcGuid, in archive order.
+	Findings []Finding
+}
+
+// Broken returns the findings that still need attention, i.e. invalid or missing IfcGuids.
+func (r *Report) Broken() []Finding {
+	var result []Finding
+	for _, f := range r.Findings {
+		if f.Kind != FilledIfcGuid {
+			result = append(result, f)
+		}
+	}
+	return result
+}
+
+// Resolver converts an AuthoringToolId to an IFC GUID.
+type Resolver func(originatingSystem, authoringToolId string) (string, error)
+
+// DefaultResolver converts an AuthoringToolId to an IFC GUID by its format:
+// Revit UniqueIds with ifcguid.FromRevitUniqueId, decimal element ids with ifcguid.FromIntString,
+// and hexadecimal handles with ifcguid.FromAutoCadHandle.
+// Ids that could be both decimal and hexadecimal are treated as handles if the originating system is AutoCAD based.
+func DefaultResolver(originatingSystem, authoringToolId string) (string, error) {
+	id := strings.TrimSpace(authoringToolId)
+	switch {
+	case id == "":
+		return "", fmt.Errorf("no AuthoringToolId")
+	case ifcguid.IsValidRevitUniqueId(id):
+		return ifcguid.FromRevitUniqueId(id)
+	case ifcguid.IsValid(id) == nil:
+		return id, nil
+	}
+	isAutoCad := strings.Contains(strings.ToLower(originatingSystem), "autocad")
+	if _, err := strconv.ParseInt(id, 10, 64); err == nil && !isAutoCad {
+		return ifcguid.FromIntString(id)
+	}
+	if ifcguid.SchemeAutoCad.Detect(id) {
+		return ifcguid.FromAutoCadHandle(id)
+	}
+	return ifcguid.Normalize(id)
+}
+
+// Check validates the IfcGuid of every component in a BCF archive.
+func Check(r io.ReaderAt, size int64) (*Report, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	report := &Report{}
+	for _, f := range zr.File {
+		if !isViewpoint(f.Name) {
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := scanViewpoint(f.Name, data, DefaultResolver, false, report); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// CheckFile is like Check, but opens the .bcf or .bcfzip file at the given path.
+func CheckFile(name string) (*Report, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return Check(f, info.Size())
+}
+
+// Backfill copies a BCF archive to w, filling in missing IfcGuids by converting the AuthoringToolId with resolve.
+// If resolve is nil, DefaultResolver is used. Archive entries without changes are copied unchanged.
+func Backfill(r io.ReaderAt, size int64, w io.Writer, resolve Resolver) (*Report, error) {
+	if resolve == nil {
+		resolve = DefaultResolver
+	}
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	zw := zip.NewWriter(w)
+	report := &Report{}
+	for _, f := range zr.File {
+		if !isViewpoint(f.Name) {
+			if err := zw.Copy(f); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		data, err := readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		fixed, err := scanViewpoint(f.Name, data, resolve, true, report)
+		if err != nil {
+			return nil, err
+		}
+		if fixed == nil {
+			if err := zw.Copy(f); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		header := f.FileHeader
+		fw, err := zw.CreateHeader(&header)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := fw.Write(fixed); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// BackfillFile is like Backfill, but reads the archive src and writes the result to dst.
+// dst is written through a temporary file, so it may be src, and it is left unchanged if Backfill fails.
+func BackfillFile(src, dst string, resolve Resolver) (*Report, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return nil, err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return nil, err
+	}
+	var report *Report
+	err = atomicfile.Write(dst, func(w io.Writer) error {
+		var err error
+		report, err = Backfill(in, info.Size(), w, resolve)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// isViewpoint reports whether an archive entry is a viewpoint file.
+func isViewpoint(name string) bool {
+	return strings.EqualFold(path.Ext(name), ".bcfv")
+}
+
+// readZipFile reads the content of an archive entry.
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// componentTag tracks a Component element while its children are read.
+type componentTag struct {
+	Component
+	start    int64 // offset of '<'
+	end      int64 // offset after '>'
+	childTag string
+}
+
+// edit replaces data[start:end] with text.
+type edit struct {
+	start, end int
+	text       string
+}
+
+// scanViewpoint checks the components of a viewpoint and appends the findings to report.
+// If fill is true, it returns the viewpoint with missing IfcGuids filled in, or nil if nothing changed.
+func scanViewpoint(name string, data []byte, resolve Resolver, fill bool, report *Report) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var current *componentTag
+	var edits []edit
+	for {
+		start := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", name, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			if t.Name.Local == "Component" {
+				current = &componentTag{start: start, end: decoder.InputOffset()}
+				current.File = name
+				current.Line, _ = decoder.InputPos()
+				for _, a := range t.Attr {
+					switch a.Name.Local {
+					case "IfcGuid":
+						current.IfcGuid = a.Value
+					case "OriginatingSystem":
+						current.OriginatingSystem = a.Value
+					case "AuthoringToolId":
+						current.AuthoringToolId = a.Value
+					}
+				}
+			} else if current != nil {
+				current.childTag = t.Name.Local
+			}
+		case xml.CharData:
+			if current == nil {
+				continue
+			}
+			switch current.childTag {
+			case "OriginatingSystem":
+				current.OriginatingSystem += string(t)
+			case "AuthoringToolId":
+				current.AuthoringToolId += string(t)
+			}
+		case xml.EndElement:
+			if current == nil {
+				continue
+			}
+			if t.Name.Local != "Component" {
+				current.childTag = ""
+				continue
+			}
+			if e, ok := checkComponent(data, current, resolve, fill, report); ok {
+				edits = append(edits, e)
+			}
+			current = nil
+		}
+	}
+	if len(edits) == 0 {
+		return nil, nil
+	}
+	var buf bytes.Buffer
+	pos := 0
+	for _, e := range edits {
+		buf.Write(data[pos:e.start])
+		buf.WriteString(e.text)
+		pos = e.end
+	}
+	buf.Write(data[pos:])
+	return buf.Bytes(), nil
+}
+
+// checkComponent validates a single component and records the finding.
+// If fill is true and the IfcGuid can be filled in, it returns the edit that does so.
+func checkComponent(data []byte, c *componentTag, resolve Resolver, fill bool, report *Report) (edit, bool) {
+	report.Components++
+	c.OriginatingSystem = strings.TrimSpace(c.OriginatingSystem)
+	c.AuthoringToolId = strings.TrimSpace(c.AuthoringToolId)
+	if c.IfcGuid != "" {
+		if err := ifcguid.IsValid(c.IfcGuid); err != nil {
+			report.Findings = append(report.Findings, Finding{Component: c.Component, Kind: InvalidIfcGuid, Err: err})
+		}
+		return edit{}, false
+	}
+	finding := Finding{Component: c.Component, Kind: MissingIfcGuid}
+	finding.GlobalId, finding.Err = resolve(c.OriginatingSystem, c.AuthoringToolId)
+	if finding.Err == nil {
+		// A custom Resolver may return anything; only valid IFC GUIDs are suggested or written.
+		if err := ifcguid.IsValid(finding.GlobalId); err != nil {
+			finding.GlobalId, finding.Err = "", fmt.Errorf("the resolver returned an invalid IFC GUID: %w", err)
+		}
+	}
+	if finding.Err != nil || !fill {
+		report.Findings = append(report.Findings, finding)
+		return edit{}, false
+	}
+	e, ok := ifcGuidEdit(data[c.start:c.end], finding.GlobalId)
+	if !ok {
+		report.Findings = append(report.Findings, finding)
+		return edit{}, false
+	}
+	finding.Kind = FilledIfcGuid
+	report.Findings = append(report.Findings, finding)
+	e.start += int(c.start)
+	e.end += int(c.start)
+	return e, true
+}
+
+// ifcGuidEdit returns the edit that sets the IfcGuid attribute in the raw start tag of a Component element.
+// An existing empty attribute is replaced; otherwise the attribute is inserted after the element name.
+func ifcGuidEdit(tag []byte, ifcGuid string) (edit, bool) {
+	attr := `IfcGuid="` + ifcGuid + `"`
+	for _, empty := range []string{`IfcGuid=""`, `IfcGuid=''`} {
+		if i := bytes.Index(tag, []byte(empty)); i >= 0 {
+			return edit{start: i, end: i + len(empty), text: attr}, true
+		}
+	}
+	if bytes.Contains(tag, []byte("IfcGuid")) {
+		// e.g. IfcGuid = "" with white space; leave it alone rather than guessing.
+		return edit{}, false
+	}
+	nameEnd := bytes.IndexAny(tag, " \t\r\n/>")
+	if nameEnd < 0 {
+		return edit{}, false
+	}
+	return edit{start: nameEnd, end: nameEnd, text: " " + attr}, true
+}
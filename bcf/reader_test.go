@@ -0,0 +1,174 @@
+package bcf
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTopic = "6a6e2b8f-2a0c-4f3b-9d61-3c4e5f607182"
+
+const testViewpoint = `<?xml version="1.0" encoding="utf-8"?>
+<VisualizationInfo Guid="0f1e2d3c-4b5a-4968-8776-a5b4c3d2e1f0">
+  <Components>
+    <Selection>
+      <Component IfcGuid="2DWKyvjkf7PffFYiFUDNsy">
+        <OriginatingSystem>Autodesk Revit</OriginatingSystem>
+      </Component>
+      <Component IfcGuid="not-a-valid-ifc-guid!" />
+      <Component>
+        <OriginatingSystem>Autodesk Revit</OriginatingSystem>
+        <AuthoringToolId>8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e</AuthoringToolId>
+      </Component>
+      <Component IfcGuid="">
+        <OriginatingSystem>Autodesk Revit</OriginatingSystem>
+        <AuthoringToolId>123456789</AuthoringToolId>
+      </Component>
+      <Component OriginatingSystem="AutoCAD" AuthoringToolId="1A"/>
+      <Component>
+        <AuthoringToolId>unknown id</AuthoringToolId>
+      </Component>
+    </Selection>
+  </Components>
+</VisualizationInfo>
+`
+
+// writeTestArchive writes a BCF archive with a single topic and the given viewpoint.
+func writeTestArchive(t *testing.T, viewpoint string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := []struct{ name, content string }{
+		{"bcf.version", `<Version VersionId="2.1"/>`},
+		{testTopic + "/markup.bcf", `<Markup><Topic Guid="` + testTopic + `"/></Markup>`},
+		{testTopic + "/viewpoint.bcfv", viewpoint},
+	}
+	for _, f := range files {
+		w, err := zw.Create(f.name)
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, f.content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func Test_Check(t *testing.T) {
+	archive := writeTestArchive(t, testViewpoint)
+
+	report, err := Check(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	assert.Equal(t, 6, report.Components)
+	if !assert.Len(t, report.Findings, 5) {
+		return
+	}
+
+	assert.Equal(t, InvalidIfcGuid, report.Findings[0].Kind)
+	assert.Equal(t, testTopic, report.Findings[0].Topic())
+	assert.Error(t, report.Findings[0].Err)
+
+	assert.Equal(t, MissingIfcGuid, report.Findings[1].Kind)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", report.Findings[1].GlobalId)
+
+	assert.Equal(t, MissingIfcGuid, report.Findings[2].Kind)
+	assert.Equal(t, "000000000000000007MyqL", report.Findings[2].GlobalId)
+
+	assert.Equal(t, MissingIfcGuid, report.Findings[3].Kind)
+	assert.Equal(t, "AutoCAD", report.Findings[3].OriginatingSystem)
+	assert.Equal(t, "000000000000000000000Q", report.Findings[3].GlobalId)
+
+	assert.Equal(t, MissingIfcGuid, report.Findings[4].Kind)
+	assert.Error(t, report.Findings[4].Err)
+
+	assert.Len(t, report.Broken(), 5)
+}
+
+func Test_Backfill(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "issues.bcfzip")
+	dst := filepath.Join(dir, "fixed.bcfzip")
+	assert.NoError(t, os.WriteFile(src, writeTestArchive(t, testViewpoint), 0o644))
+
+	report, err := BackfillFile(src, dst, nil)
+	assert.NoError(t, err)
+	assert.Len(t, report.Findings, 5)
+	assert.Len(t, report.Broken(), 2)
+
+	fixed, err := CheckFile(dst)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, fixed.Components)
+	if assert.Len(t, fixed.Findings, 2) {
+		assert.Equal(t, InvalidIfcGuid, fixed.Findings[0].Kind)
+		assert.Equal(t, MissingIfcGuid, fixed.Findings[1].Kind)
+	}
+
+	zr, err := zip.OpenReader(dst)
+	assert.NoError(t, err)
+	defer zr.Close()
+	assert.Len(t, zr.File, 3)
+	for _, f := range zr.File {
+		data, err := readZipFile(f)
+		assert.NoError(t, err)
+		if !isViewpoint(f.Name) {
+			continue
+		}
+		assert.Contains(t, string(data), `<Component IfcGuid="2DWKyvjkf7PffFYiFUDNsy">
+        <OriginatingSystem>Autodesk Revit</OriginatingSystem>
+        <AuthoringToolId>8d814f39`)
+		assert.Contains(t, string(data), `<Component IfcGuid="000000000000000007MyqL">`)
+		assert.Contains(t, string(data), `<Component IfcGuid="000000000000000000000Q" OriginatingSystem="AutoCAD" AuthoringToolId="1A"/>`)
+	}
+}
+
+func Test_Backfill_with_invalid_resolver_result(t *testing.T) {
+	archive := writeTestArchive(t, testViewpoint)
+	var out bytes.Buffer
+	report, err := Backfill(bytes.NewReader(archive), int64(len(archive)), &out, func(string, string) (string, error) {
+		return `x" Injected="y`, nil
+	})
+	assert.NoError(t, err)
+	for _, f := range report.Findings {
+		assert.NotEqual(t, FilledIfcGuid, f.Kind)
+		assert.Empty(t, f.GlobalId)
+		if f.Kind == MissingIfcGuid {
+			assert.ErrorContains(t, f.Err, "the resolver returned an invalid IFC GUID")
+		}
+	}
+	fixed, err := Check(bytes.NewReader(out.Bytes()), int64(out.Len()))
+	assert.NoError(t, err)
+	assert.Len(t, fixed.Findings, len(report.Findings))
+}
+
+func Test_BackfillFile_in_place(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "issues.bcfzip")
+	assert.NoError(t, os.WriteFile(src, writeTestArchive(t, testViewpoint), 0o644))
+
+	_, err := BackfillFile(src, src, nil)
+	assert.NoError(t, err)
+	fixed, err := CheckFile(src)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, fixed.Components)
+	assert.Len(t, fixed.Findings, 2)
+
+	// A file that isn't an archive is left unchanged.
+	invalid := filepath.Join(t.TempDir(), "invalid.bcfzip")
+	assert.NoError(t, os.WriteFile(invalid, []byte("not a zip archive"), 0o644))
+	_, err = BackfillFile(invalid, invalid, nil)
+	assert.Error(t, err)
+	data, err := os.ReadFile(invalid)
+	assert.NoError(t, err)
+	assert.Equal(t, "not a zip archive", string(data))
+}
+
+func Test_Check_with_invalid_archive(t *testing.T) {
+	_, err := Check(bytes.NewReader([]byte("not a zip")), 9)
+	assert.Error(t, err)
+
+	archive := writeTestArchive(t, "<VisualizationInfo><Components>")
+	_, err = Check(bytes.NewReader(archive), int64(len(archive)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,485 @@
+package bcf
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/internal/atomicfile"
+)
+
+// Supported BCF versions.
+const (
+	Version21 = "2.1"
+	Version30 = "3.0"
+)
+
+// Archive is a BCF archive with a list of topics, see Write.
+type Archive struct {
+	// Version is the BCF version to write, Version21 or Version30.
+	Version string
+	// Topics are the issues in the archive.
+	Topics []*Topic
+}
+
+// Topic is a single BCF issue.
+type Topic struct {
+	// Guid identifies the topic. A random one is generated if it is empty.
+	Guid string
+	// Title is a short description of the issue. It is required.
+	Title string
+	// TopicType, e.g. "Error" or "Issue". BCF 3.0 requires it; "Issue" is used if it is empty.
+	TopicType string
+	// TopicStatus, e.g. "Open". BCF 3.0 requires it; "Open" is used if it is empty.
+	TopicStatus string
+	Priority    string
+	Labels      []string
+	AssignedTo  string
+	Description string
+	// CreationDate defaults to the time of writing.
+	CreationDate time.Time
+	// CreationAuthor is required.
+	CreationAuthor string
+	Comments       []*Comment
+	Viewpoints     []*Viewpoint
+}
+
+// Comment is a comment on a topic.
+type Comment struct {
+	// Guid identifies the comment. A random one is generated if it is empty.
+	Guid string
+	// Date defaults to the time of writing.
+	Date   time.Time
+	Author string
+	Text   string
+	// Viewpoint optionally links the comment to one of the topic's viewpoints.
+	Viewpoint *Viewpoint
+}
+
+// Viewpoint is a view on the model, with component selection and visibility given as IFC GUIDs.
+type Viewpoint struct {
+	// Guid identifies the viewpoint. A random one is generated if it is empty.
+	Guid string
+	// Selection lists the GlobalIds of the selected elements.
+	Selection []string
+	// DefaultVisibility is the visibility of all elements not listed in Exceptions.
+	DefaultVisibility bool
+	// Exceptions lists the GlobalIds of elements whose visibility is the opposite of DefaultVisibility.
+	Exceptions []string
+	// Camera is optional.
+	Camera *PerspectiveCamera
+}
+
+// PerspectiveCamera defines the camera of a viewpoint.
+type PerspectiveCamera struct {
+	ViewPoint   [3]float64
+	Direction   [3]float64
+	UpVector    [3]float64
+	FieldOfView float64
+	// AspectRatio is only written for BCF 3.0, which requires it; 1 is used if it is zero.
+	AspectRatio float64
+}
+
+// NewTopic returns a topic with a random guid, the given title and author, and the current time.
+func NewTopic(title, author string) *Topic {
+	return &Topic{
+		Guid:           uuid.NewString(),
+		Title:          title,
+		CreationAuthor: author,
+		CreationDate:   time.Now().UTC(),
+	}
+}
+
+// AddComment adds a comment to the topic and returns it.
+func (t *Topic) AddComment(author, text string) *Comment {
+	c := &Comment{Guid: uuid.NewString(), Date: time.Now().UTC(), Author: author, Text: text}
+	t.Comments = append(t.Comments, c)
+	return c
+}
+
+// AddViewpoint adds a viewpoint that selects the given GlobalIds, with all elements visible, and returns it.
+func (t *Topic) AddViewpoint(selection ...string) *Viewpoint {
+	v := &Viewpoint{Guid: uuid.NewString(), Selection: selection, DefaultVisibility: true}
+	t.Viewpoints = append(t.Viewpoints, v)
+	return v
+}
+
+// Write writes the archive in BCF format to w.
+// Every GlobalId is validated with ifcguid.IsValid; an invalid one results in an error.
+func Write(w io.Writer, a *Archive) error {
+	if a.Version != Version21 && a.Version != Version30 {
+		return fmt.Errorf("unsupported BCF version: %q", a.Version)
+	}
+	now := time.Now().UTC()
+	for i, t := range a.Topics {
+		if err := t.prepare(i, now); err != nil {
+			return err
+		}
+	}
+	zw := zip.NewWriter(w)
+	if err := writeXml(zw, "bcf.version", func(x *xmlWriter) { x.version(a.Version) }); err != nil {
+		return err
+	}
+	if a.Version == Version30 {
+		if err := writeXml(zw, "extensions.xml", func(x *xmlWriter) { x.extensions(a.Topics) }); err != nil {
+			return err
+		}
+	}
+	for _, t := range a.Topics {
+		if err := writeXml(zw, t.Guid+"/markup.bcf", func(x *xmlWriter) { x.markup(a.Version, t) }); err != nil {
+			return err
+		}
+		for i, v := range t.Viewpoints {
+			name := t.Guid + "/" + viewpointFile(i, v)
+			if err := writeXml(zw, name, func(x *xmlWriter) { x.viewpoint(a.Version, v) }); err != nil {
+				return err
+			}
+		}
+	}
+	return zw.Close()
+}
+
+// WriteFile writes the archive to the file with the given name, see Write.
+// The archive is written to a temporary file first, so a failed write doesn't leave a partial archive behind.
+func WriteFile(name string, a *Archive) error {
+	return atomicfile.Write(name, func(w io.Writer) error {
+		return Write(w, a)
+	})
+}
+
+// prepare fills in defaults and validates the i-th topic of the archive.
+// Errors found before the Guid is filled in identify the topic by its index, since it may not have a Guid yet.
+func (t *Topic) prepare(i int, now time.Time) error {
+	if t.Title == "" {
+		return fmt.Errorf("topic %d: the title must not be empty", i)
+	}
+	if t.CreationAuthor == "" {
+		return fmt.Errorf("topic %d: the creation author must not be empty", i)
+	}
+	if t.Guid == "" {
+		t.Guid = uuid.NewString()
+	}
+	if t.CreationDate.IsZero() {
+		t.CreationDate = now
+	}
+	for _, c := range t.Comments {
+		if c.Guid == "" {
+			c.Guid = uuid.NewString()
+		}
+		if c.Date.IsZero() {
+			c.Date = now
+		}
+	}
+	for _, v := range t.Viewpoints {
+		if v.Guid == "" {
+			v.Guid = uuid.NewString()
+		}
+		for _, ids := range [][]string{v.Selection, v.Exceptions} {
+			for _, id := range ids {
+				if err := ifcguid.IsValid(id); err != nil {
+					return fmt.Errorf("topic %s: viewpoint %s: %q: %w", t.Guid, v.Guid, id, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// viewpointFile returns the file name of the i-th viewpoint of a topic.
+// The first viewpoint is the default viewpoint.bcfv, as expected by most BCF tools.
+func viewpointFile(i int, v *Viewpoint) string {
+	if i == 0 {
+		return "viewpoint.bcfv"
+	}
+	return v.Guid + ".bcfv"
+}
+
+// writeXml adds an XML file to the archive, with the content written by body.
+func writeXml(zw *zip.Writer, name string, body func(x *xmlWriter)) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	x := &xmlWriter{enc: xml.NewEncoder(w)}
+	x.enc.Indent("", "  ")
+	body(x)
+	if x.err == nil {
+		x.err = x.enc.Flush()
+	}
+	return x.err
+}
+
+// xmlWriter writes XML elements and keeps the first error.
+type xmlWriter struct {
+	enc *xml.Encoder
+	err error
+}
+
+func (x *xmlWriter) token(t xml.Token) {
+	if x.err == nil {
+		x.err = x.enc.EncodeToken(t)
+	}
+}
+
+// start writes a start element with the given attributes, as name/value pairs.
+func (x *xmlWriter) start(name string, attrs ...string) {
+	se := xml.StartElement{Name: xml.Name{Local: name}}
+	for i := 0; i+1 < len(attrs); i += 2 {
+		se.Attr = append(se.Attr, xml.Attr{Name: xml.Name{Local: attrs[i]}, Value: attrs[i+1]})
+	}
+	x.token(se)
+}
+
+func (x *xmlWriter) end(name string) {
+	x.token(xml.EndElement{Name: xml.Name{Local: name}})
+}
+
+func (x *xmlWriter) empty(name string, attrs ...string) {
+	x.start(name, attrs...)
+	x.end(name)
+}
+
+// text writes an element with text content.
+func (x *xmlWriter) text(name, value string) {
+	x.start(name)
+	x.token(xml.CharData(value))
+	x.end(name)
+}
+
+// optional writes an element with text content, unless value is empty.
+func (x *xmlWriter) optional(name, value string) {
+	if value != "" {
+		x.text(name, value)
+	}
+}
+
+func (x *xmlWriter) version(version string) {
+	x.start("Version", "VersionId", version)
+	if version == Version21 {
+		x.text("DetailedVersion", version)
+	}
+	x.end("Version")
+}
+
+// extensions writes the extensions.xml of BCF 3.0, which declares the values used by the topics:
+// a topic type, status, priority, label or assignee that isn't declared is invalid.
+func (x *xmlWriter) extensions(topics []*Topic) {
+	var types, statuses, priorities, labels, users values
+	for _, t := range topics {
+		topicType, topicStatus := t.types(Version30)
+		types.add(topicType)
+		statuses.add(topicStatus)
+		priorities.add(t.Priority)
+		labels.add(t.Labels...)
+		users.add(t.AssignedTo)
+	}
+	x.start("Extensions")
+	x.list("TopicTypes", "TopicType", types)
+	x.list("TopicStatuses", "TopicStatus", statuses)
+	x.list("Priorities", "Priority", priorities)
+	x.list("TopicLabels", "TopicLabel", labels)
+	x.list("Users", "User", users)
+	x.end("Extensions")
+}
+
+// list writes a list element with an element for each value, unless there are no values.
+func (x *xmlWriter) list(name, item string, v values) {
+	if len(v.list) == 0 {
+		return
+	}
+	x.start(name)
+	for _, value := range v.list {
+		x.text(item, value)
+	}
+	x.end(name)
+}
+
+// values is a list of distinct, non-empty values, in the order they were added.
+type values struct {
+	list []string
+	seen map[string]bool
+}
+
+func (v *values) add(values ...string) {
+	for _, value := range values {
+		if value == "" || v.seen[value] {
+			continue
+		}
+		if v.seen == nil {
+			v.seen = map[string]bool{}
+		}
+		v.seen[value] = true
+		v.list = append(v.list, value)
+	}
+}
+
+// types returns the topic type and status written for the given version.
+// BCF 3.0 requires both, so "Issue" and "Open" are used if they are empty.
+func (t *Topic) types(version string) (string, string) {
+	topicType, topicStatus := t.TopicType, t.TopicStatus
+	if version == Version30 {
+		if topicType == "" {
+			topicType = "Issue"
+		}
+		if topicStatus == "" {
+			topicStatus = "Open"
+		}
+	}
+	return topicType, topicStatus
+}
+
+func (x *xmlWriter) markup(version string, t *Topic) {
+	topicType, topicStatus := t.types(version)
+	var attrs []string
+	attrs = append(attrs, "Guid", t.Guid)
+	if topicType != "" {
+		attrs = append(attrs, "TopicType", topicType)
+	}
+	if topicStatus != "" {
+		attrs = append(attrs, "TopicStatus", topicStatus)
+	}
+
+	x.start("Markup")
+	x.start("Topic", attrs...)
+	x.text("Title", t.Title)
+	x.optional("Priority", t.Priority)
+	if version == Version30 && len(t.Labels) > 0 {
+		x.start("Labels")
+		for _, l := range t.Labels {
+			x.text("Label", l)
+		}
+		x.end("Labels")
+	} else {
+		for _, l := range t.Labels {
+			x.text("Labels", l)
+		}
+	}
+	x.text("CreationDate", formatDate(t.CreationDate))
+	x.text("CreationAuthor", t.CreationAuthor)
+	x.optional("AssignedTo", t.AssignedTo)
+	x.optional("Description", t.Description)
+	if version == Version30 {
+		// BCF 3.0 nests comments and viewpoints in the topic.
+		x.comments(version, t)
+		x.viewpoints(version, t)
+		x.end("Topic")
+	} else {
+		x.end("Topic")
+		x.comments(version, t)
+		x.viewpoints(version, t)
+	}
+	x.end("Markup")
+}
+
+func (x *xmlWriter) comments(version string, t *Topic) {
+	if len(t.Comments) == 0 {
+		return
+	}
+	if version == Version30 {
+		x.start("Comments")
+	}
+	for _, c := range t.Comments {
+		x.start("Comment", "Guid", c.Guid)
+		x.text("Date", formatDate(c.Date))
+		x.text("Author", c.Author)
+		if version == Version21 || c.Text != "" {
+			x.text("Comment", c.Text)
+		}
+		if c.Viewpoint != nil {
+			x.empty("Viewpoint", "Guid", c.Viewpoint.Guid)
+		}
+		x.end("Comment")
+	}
+	if version == Version30 {
+		x.end("Comments")
+	}
+}
+
+func (x *xmlWriter) viewpoints(version string, t *Topic) {
+	if len(t.Viewpoints) == 0 {
+		return
+	}
+	if version == Version30 {
+		x.start("Viewpoints")
+	}
+	for i, v := range t.Viewpoints {
+		// The element is named Viewpoints in BCF 2.1 and ViewPoint in BCF 3.0.
+		name := "Viewpoints"
+		if version == Version30 {
+			name = "ViewPoint"
+		}
+		x.start(name, "Guid", v.Guid)
+		x.text("Viewpoint", viewpointFile(i, v))
+		x.end(name)
+	}
+	if version == Version30 {
+		x.end("Viewpoints")
+	}
+}
+
+func (x *xmlWriter) viewpoint(version string, v *Viewpoint) {
+	x.start("VisualizationInfo", "Guid", v.Guid)
+	x.start("Components")
+	if len(v.Selection) > 0 {
+		x.start("Selection")
+		x.components(v.Selection)
+		x.end("Selection")
+	}
+	x.start("Visibility", "DefaultVisibility", strconv.FormatBool(v.DefaultVisibility))
+	if len(v.Exceptions) > 0 {
+		x.start("Exceptions")
+		x.components(v.Exceptions)
+		x.end("Exceptions")
+	}
+	x.end("Visibility")
+	x.end("Components")
+	if c := v.Camera; c != nil {
+		x.start("PerspectiveCamera")
+		x.vector("CameraViewPoint", "X", "Y", "Z", c.ViewPoint)
+		x.vector("CameraDirection", "X", "Y", "Z", c.Direction)
+		x.vector("CameraUpVector", "X", "Y", "Z", c.UpVector)
+		x.text("FieldOfView", formatFloat(c.FieldOfView))
+		if version == Version30 {
+			aspectRatio := c.AspectRatio
+			if aspectRatio == 0 {
+				aspectRatio = 1
+			}
+			x.text("AspectRatio", formatFloat(aspectRatio))
+		}
+		x.end("PerspectiveCamera")
+	}
+	x.end("VisualizationInfo")
+}
+
+func (x *xmlWriter) components(ifcGuids []string) {
+	for _, id := range ifcGuids {
+		x.empty("Component", "IfcGuid", id)
+	}
+}
+
+func (x *xmlWriter) vector(name, nx, ny, nz string, v [3]float64) {
+	x.start(name)
+	x.text(nx, formatFloat(v[0]))
+	x.text(ny, formatFloat(v[1]))
+	x.text(nz, formatFloat(v[2]))
+	x.end(name)
+}
+
+// formatDate formats a time as xs:dateTime.
+func formatDate(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
+
+// formatFloat formats a float as xs:double.
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
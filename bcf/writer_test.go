@@ -0,0 +1,218 @@
+package bcf
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// childNames returns the names of the child elements of the first element with the given name.
+func childNames(t *testing.T, data []byte, parent string) []string {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := -1
+	var names []string
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return names
+		}
+		if !assert.NoError(t, err) {
+			return nil
+		}
+		switch tok := token.(type) {
+		case xml.StartElement:
+			if depth >= 0 {
+				depth++
+				if depth == 1 {
+					names = append(names, tok.Name.Local)
+				}
+			} else if tok.Name.Local == parent {
+				depth = 0
+			}
+		case xml.EndElement:
+			if depth == 0 {
+				return names
+			}
+			if depth > 0 {
+				depth--
+			}
+		}
+	}
+}
+
+func testArchive(version string) *Archive {
+	topic := NewTopic("Duplicate GlobalId", "qa@example.com")
+	topic.Guid = testTopic
+	topic.TopicType = "Error"
+	topic.Labels = []string{"GlobalId", "QA"}
+	topic.CreationDate = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	topic.Description = "Two walls share a GlobalId."
+	v := topic.AddViewpoint("2DWKyvjkf7PffFYiFUDNsy", "0mXQZaOVr7Tf$n6oIcHifF")
+	v.DefaultVisibility = false
+	v.Exceptions = []string{"2DWKyvjkf7PffFYiFUDNsy"}
+	v.Camera = &PerspectiveCamera{
+		ViewPoint:   [3]float64{10, 10, 5},
+		Direction:   [3]float64{-1, -1, -0.5},
+		UpVector:    [3]float64{0, 0, 1},
+		FieldOfView: 60,
+	}
+	topic.AddViewpoint("3eOl_mPvD3GArUOj6ASFdZ")
+	c := topic.AddComment("qa@example.com", "Found by the nightly check.")
+	c.Viewpoint = v
+	return &Archive{Version: version, Topics: []*Topic{topic}}
+}
+
+func readArchive(t *testing.T, data []byte) map[string][]byte {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	files := map[string][]byte{}
+	for _, f := range zr.File {
+		content, err := readZipFile(f)
+		assert.NoError(t, err)
+		files[f.Name] = content
+	}
+	return files
+}
+
+// The expected element sequences below are those of the buildingSMART BCF XSDs
+// (markup.xsd, visinfo.xsd, version.xsd and extensions.xsd); the XSDs themselves aren't part of the tests.
+func Test_Write_BCF21(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, testArchive(Version21)))
+	files := readArchive(t, buf.Bytes())
+
+	assert.Len(t, files, 4)
+	assert.Equal(t, []string{"DetailedVersion"}, childNames(t, files["bcf.version"], "Version"))
+
+	markup := files[testTopic+"/markup.bcf"]
+	assert.Equal(t, []string{"Topic", "Comment", "Viewpoints", "Viewpoints"}, childNames(t, markup, "Markup"))
+	assert.Equal(t,
+		[]string{"Title", "Labels", "Labels", "CreationDate", "CreationAuthor", "Description"},
+		childNames(t, markup, "Topic"))
+	assert.Equal(t, []string{"Date", "Author", "Comment", "Viewpoint"}, childNames(t, markup, "Comment"))
+	assert.Contains(t, string(markup), `TopicType="Error"`)
+	assert.NotContains(t, string(markup), `TopicStatus`)
+
+	viewpoint := files[testTopic+"/viewpoint.bcfv"]
+	assert.Equal(t, []string{"Components", "PerspectiveCamera"}, childNames(t, viewpoint, "VisualizationInfo"))
+	assert.Equal(t, []string{"Selection", "Visibility"}, childNames(t, viewpoint, "Components"))
+	assert.Equal(t,
+		[]string{"CameraViewPoint", "CameraDirection", "CameraUpVector", "FieldOfView"},
+		childNames(t, viewpoint, "PerspectiveCamera"))
+
+	report, err := Check(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Components)
+	assert.Empty(t, report.Findings)
+}
+
+func Test_Write_BCF30(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, testArchive(Version30)))
+	files := readArchive(t, buf.Bytes())
+
+	assert.Len(t, files, 5)
+	// Every topic type, status and label used by the topics is declared.
+	extensions := files["extensions.xml"]
+	assert.Equal(t, []string{"TopicTypes", "TopicStatuses", "TopicLabels"}, childNames(t, extensions, "Extensions"))
+	assert.Equal(t, []string{"TopicType"}, childNames(t, extensions, "TopicTypes"))
+	assert.Contains(t, string(extensions), "<TopicType>Error</TopicType>")
+	assert.Contains(t, string(extensions), "<TopicStatus>Open</TopicStatus>")
+	assert.Equal(t, []string{"TopicLabel", "TopicLabel"}, childNames(t, extensions, "TopicLabels"))
+	assert.Empty(t, childNames(t, files["bcf.version"], "Version"))
+	assert.Contains(t, string(files["bcf.version"]), `VersionId="3.0"`)
+
+	markup := files[testTopic+"/markup.bcf"]
+	assert.Equal(t, []string{"Topic"}, childNames(t, markup, "Markup"))
+	assert.Equal(t,
+		[]string{"Title", "Labels", "CreationDate", "CreationAuthor", "Description", "Comments", "Viewpoints"},
+		childNames(t, markup, "Topic"))
+	assert.Equal(t, []string{"Label", "Label"}, childNames(t, markup, "Labels"))
+	assert.Equal(t, []string{"ViewPoint", "ViewPoint"}, childNames(t, markup, "Viewpoints"))
+	assert.Contains(t, string(markup), `TopicStatus="Open"`)
+
+	viewpoint := files[testTopic+"/viewpoint.bcfv"]
+	assert.Equal(t, []string{"Selection", "Visibility"}, childNames(t, viewpoint, "Components"))
+	assert.Equal(t, []string{"Exceptions"}, childNames(t, viewpoint, "Visibility"))
+	assert.Equal(t,
+		[]string{"CameraViewPoint", "CameraDirection", "CameraUpVector", "FieldOfView", "AspectRatio"},
+		childNames(t, viewpoint, "PerspectiveCamera"))
+
+	var secondViewpoint []byte
+	for name, content := range files {
+		if strings.HasSuffix(name, ".bcfv") && !strings.HasSuffix(name, "/viewpoint.bcfv") {
+			secondViewpoint = content
+		}
+	}
+	assert.Contains(t, string(secondViewpoint), `<Component IfcGuid="3eOl_mPvD3GArUOj6ASFdZ"></Component>`)
+
+	report, err := Check(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.Components)
+	assert.Empty(t, report.Findings)
+}
+
+func Test_Write_BCF30_extensions(t *testing.T) {
+	a := testArchive(Version30)
+	second := NewTopic("Invalid GlobalId", "qa@example.com")
+	second.Priority = "High"
+	second.Labels = []string{"QA", "Revit"}
+	second.AssignedTo = "architect@example.com"
+	a.Topics = append(a.Topics, second)
+
+	var buf bytes.Buffer
+	assert.NoError(t, Write(&buf, a))
+	extensions := readArchive(t, buf.Bytes())["extensions.xml"]
+	assert.Equal(t,
+		[]string{"TopicTypes", "TopicStatuses", "Priorities", "TopicLabels", "Users"},
+		childNames(t, extensions, "Extensions"))
+	assert.Contains(t, string(extensions), "<TopicType>Error</TopicType>\n    <TopicType>Issue</TopicType>")
+	assert.Equal(t, []string{"TopicStatus"}, childNames(t, extensions, "TopicStatuses"))
+	assert.Equal(t, []string{"TopicLabel", "TopicLabel", "TopicLabel"}, childNames(t, extensions, "TopicLabels"))
+	assert.Contains(t, string(extensions), "<User>architect@example.com</User>")
+}
+
+func Test_Write_with_invalid_data(t *testing.T) {
+	a := testArchive(Version21)
+	a.Topics[0].Viewpoints[0].Selection = append(a.Topics[0].Viewpoints[0].Selection, "invalid")
+	assert.ErrorContains(t, Write(io.Discard, a), "invalid")
+
+	a = testArchive("2.0")
+	assert.Error(t, Write(io.Discard, a))
+
+	a = testArchive(Version30)
+	a.Topics[0].Title = ""
+	assert.EqualError(t, Write(io.Discard, a), "topic 0: the title must not be empty")
+
+	a = testArchive(Version30)
+	a.Topics = append(a.Topics, &Topic{Title: "Without author"})
+	assert.EqualError(t, Write(io.Discard, a), "topic 1: the creation author must not be empty")
+}
+
+func Test_WriteFile(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "issues.bcf")
+	assert.NoError(t, WriteFile(name, testArchive(Version30)))
+	before, err := os.ReadFile(name)
+	assert.NoError(t, err)
+
+	a := testArchive(Version30)
+	a.Topics[0].Title = ""
+	assert.Error(t, WriteFile(name, a))
+	after, err := os.ReadFile(name)
+	assert.NoError(t, err)
+	assert.Equal(t, before, after, "a failed write keeps the existing archive")
+
+	assert.Error(t, WriteFile(filepath.Join(dir, "new.bcf"), a))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "a failed write leaves no partial archive")
+}
@@ -0,0 +1,176 @@
+package ifcguid
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strings"
+)
+
+// CaseCollision is a group of distinct GlobalIds that are equal when case is ignored.
+//
+// IFC GUIDs are case-sensitive, because the base 64 alphabet uses both 'A'-'Z' and 'a'-'z'.
+// Case-insensitive systems, like SQL Server with its default collations, Windows file names and spreadsheet lookups,
+// treat the GlobalIds of a group as the same value.
+type CaseCollision struct {
+	// Key is the case-folded GlobalId, see CaseFold.
+	Key string
+	// GlobalIds are the distinct GlobalIds that fold to Key, sorted.
+	GlobalIds []string
+}
+
+// CaseCollisionRisk is an estimate of the probability of case collisions in a population of GlobalIds.
+type CaseCollisionRisk struct {
+	// Population is the number of GlobalIds the estimate is for.
+	Population int
+	// PairProbability is the probability that two distinct GlobalIds collide when case is ignored.
+	PairProbability float64
+	// ExpectedCollisions is the expected number of colliding pairs in the population.
+	ExpectedCollisions float64
+	// Probability is the probability of at least one collision in the population.
+	Probability float64
+}
+
+// CaseFold returns the GlobalId in lower case, i.e. the value that case-insensitive systems compare.
+func CaseFold(ifcGuid string) string {
+	return strings.ToLower(ifcGuid)
+}
+
+// CaseCollisionChecker finds case collisions in a stream of GlobalIds, see CaseCollision.
+// The zero value is ready to use.
+type CaseCollisionChecker struct {
+	groups map[string]map[string]struct{}
+}
+
+// Add adds a GlobalId to the checker. Exact duplicates are ignored.
+func (c *CaseCollisionChecker) Add(ifcGuid string) {
+	if c.groups == nil {
+		c.groups = map[string]map[string]struct{}{}
+	}
+	key := CaseFold(ifcGuid)
+	group, ok := c.groups[key]
+	if !ok {
+		group = map[string]struct{}{}
+		c.groups[key] = group
+	}
+	group[ifcGuid] = struct{}{}
+}
+
+// Collisions returns the groups of GlobalIds added so far that collide when case is ignored, sorted by key.
+func (c *CaseCollisionChecker) Collisions() []CaseCollision {
+	var result []CaseCollision
+	for key, group := range c.groups {
+		if len(group) < 2 {
+			continue
+		}
+		ids := make([]string, 0, len(group))
+		for id := range group {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		result = append(result, CaseCollision{Key: key, GlobalIds: ids})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Key < result[j].Key })
+	return result
+}
+
+// FindCaseCollisions returns the groups of GlobalIds that collide when case is ignored.
+func FindCaseCollisions(ifcGuids []string) []CaseCollision {
+	var c CaseCollisionChecker
+	for _, id := range ifcGuids {
+		c.Add(id)
+	}
+	return c.Collisions()
+}
+
+// FindCaseCollisionsIn reads GlobalIds from r, one per line, and returns the groups that collide when case is ignored.
+// Leading and trailing white space and empty lines are ignored.
+func FindCaseCollisionsIn(r io.Reader) ([]CaseCollision, error) {
+	var c CaseCollisionChecker
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if id := strings.TrimSpace(scanner.Text()); id != "" {
+			c.Add(id)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return c.Collisions(), nil
+}
+
+// RandomCaseCollisionRisk estimates the risk of case collisions among population random GlobalIds,
+// e.g. GlobalIds created by New.
+//
+// The first character of a GlobalId has 4 possible values, and the other 21 have 64 each,
+// of which 52 are letters that collide with another letter when case is ignored.
+func RandomCaseCollisionRisk(population int) CaseCollisionRisk {
+	// For a uniform character, the probability that two characters are equal is 1/64,
+	// and that they are equal ignoring case is (12 + 26*4) / 64² = 116/4096.
+	logSameFolded := math.Log(1.0/4) + 21*math.Log(116.0/4096)
+	logSame := math.Log(1.0/4) + 21*math.Log(1.0/64)
+	return newCaseCollisionRisk(population, math.Exp(logSameFolded)-math.Exp(logSame))
+}
+
+// EstimateCaseCollisionRisk estimates the risk of case collisions in a population of GlobalIds like the given sample.
+//
+// GlobalIds derived from CAD identifiers are far from random: for example, the GlobalIds of a Revit model
+// often share the first characters and only differ at the end, which makes case collisions much more likely.
+// The estimate uses the frequency of each character at each position in the sample, assuming independent positions.
+// If population is zero, the size of the sample is used.
+func EstimateCaseCollisionRisk(sample []string, population int) CaseCollisionRisk {
+	if population == 0 {
+		population = len(sample)
+	}
+	var counts [22]map[byte]int
+	n := 0
+	for _, id := range sample {
+		if len(id) != 22 {
+			continue
+		}
+		n++
+		for i := 0; i < 22; i++ {
+			if counts[i] == nil {
+				counts[i] = map[byte]int{}
+			}
+			counts[i][id[i]]++
+		}
+	}
+	if n == 0 {
+		return newCaseCollisionRisk(population, 0)
+	}
+	logSameFolded, logSame := 0.0, 0.0
+	for i := 0; i < 22; i++ {
+		folded := map[byte]int{}
+		same := 0.0
+		for ch, count := range counts[i] {
+			f := float64(count) / float64(n)
+			same += f * f
+			folded[byte(strings.ToLower(string(ch))[0])] += count
+		}
+		sameFolded := 0.0
+		for _, count := range folded {
+			f := float64(count) / float64(n)
+			sameFolded += f * f
+		}
+		logSameFolded += math.Log(sameFolded)
+		logSame += math.Log(same)
+	}
+	return newCaseCollisionRisk(population, math.Max(0, math.Exp(logSameFolded)-math.Exp(logSame)))
+}
+
+// newCaseCollisionRisk returns the risk for a population, given the collision probability of a pair.
+func newCaseCollisionRisk(population int, pairProbability float64) CaseCollisionRisk {
+	pairs := float64(population) * float64(population-1) / 2
+	if population < 2 {
+		pairs = 0
+	}
+	expected := pairs * pairProbability
+	return CaseCollisionRisk{
+		Population:         population,
+		PairProbability:    pairProbability,
+		ExpectedCollisions: expected,
+		Probability:        -math.Expm1(-expected),
+	}
+}
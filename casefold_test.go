@@ -0,0 +1,72 @@
+package ifcguid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_FindCaseCollisions(t *testing.T) {
+	ids := []string{
+		"05td0d3Oz7khUJKT2KE2Aq",
+		"05td0d3Oz7khUJKT2KE2AQ",
+		"05td0d3Oz7khUJKT2KE2aq",
+		"05td0d3Oz7khUJKT2KE2Aq", // exact duplicate, not a case collision
+		"05td0d3Oz7khUJKT2KE2hp",
+		"2DWKyvjkf7PffFYiFUDNsy",
+		"2dwkyvjkf7pfffyifudnsy",
+		"0mXQZaOVr7Tf$n6oIcHifF",
+	}
+
+	got := FindCaseCollisions(ids)
+	assert.Equal(t, []CaseCollision{
+		{
+			Key:       "05td0d3oz7khujkt2ke2aq",
+			GlobalIds: []string{"05td0d3Oz7khUJKT2KE2AQ", "05td0d3Oz7khUJKT2KE2Aq", "05td0d3Oz7khUJKT2KE2aq"},
+		},
+		{
+			Key:       "2dwkyvjkf7pfffyifudnsy",
+			GlobalIds: []string{"2DWKyvjkf7PffFYiFUDNsy", "2dwkyvjkf7pfffyifudnsy"},
+		},
+	}, got)
+
+	fromReader, err := FindCaseCollisionsIn(strings.NewReader(strings.Join(ids, "\n  \n")))
+	assert.NoError(t, err)
+	assert.Equal(t, got, fromReader)
+
+	assert.Empty(t, FindCaseCollisions([]string{"2DWKyvjkf7PffFYiFUDNsy", "2DWKyvjkf7PffFYiFUDNsy"}))
+}
+
+func Test_RandomCaseCollisionRisk(t *testing.T) {
+	risk := RandomCaseCollisionRisk(1_000_000_000)
+	assert.Equal(t, 1_000_000_000, risk.Population)
+	assert.Greater(t, risk.PairProbability, 0.0)
+	assert.Less(t, risk.PairProbability, 1e-30)
+	assert.Less(t, risk.Probability, 1e-12)
+
+	assert.Zero(t, RandomCaseCollisionRisk(1).ExpectedCollisions)
+}
+
+func Test_EstimateCaseCollisionRisk(t *testing.T) {
+	// GlobalIds that only differ in the last character, like those of Revit elements of one model.
+	var sample []string
+	for _, ch := range _conversionTable {
+		sample = append(sample, "05td0d3Oz7khUJKT2KE2A"+string(ch))
+	}
+	risk := EstimateCaseCollisionRisk(sample, 0)
+	assert.Equal(t, len(sample), risk.Population)
+	// 116/4096 - 1/64 for the last character, all others are equal.
+	assert.InDelta(t, 116.0/4096-1.0/64, risk.PairProbability, 1e-12)
+	assert.Greater(t, risk.Probability, 0.99)
+
+	var random []string
+	for i := 0; i < 1000; i++ {
+		id, err := New()
+		assert.NoError(t, err)
+		random = append(random, id)
+	}
+	assert.Less(t, EstimateCaseCollisionRisk(random, 1000).Probability, risk.Probability)
+
+	assert.Zero(t, EstimateCaseCollisionRisk(nil, 100).PairProbability)
+}
@@ -0,0 +1,335 @@
+// Command ifcguid works with the GlobalIds of IFC files.
+//
+// Usage:
+//
+//	ifcguid <command> [flags] [file]
+//
+// The commands are:
+//
+//	anonymize  remove the node ids (MAC addresses) from the time-based GlobalIds of an IFC-SPF file
+//	diff       list the entities added, removed and changed between two IFC-SPF files, matched by GlobalId
+//	fork       replace every GlobalId of an IFC-SPF file with a keyed, deterministic new GlobalId
+//	timebased  list the time-based GlobalIds of an IFC-SPF file, with their creation time and node id
+//	unfork     restore the original GlobalIds of a forked IFC-SPF file
+//
+// Run "ifcguid <command> -h" for the flags of a command.
+// Files are read from standard input if no file, or "-", is given.
+// Files may be .ifczip archives; the commands that rewrite files then write an .ifczip archive too.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/ifczip"
+	"github.com/woweh/ifcguid/internal/atomicfile"
+	"github.com/woweh/ifcguid/spf"
+)
+
+const (
+	// _secretEnv is the environment variable that holds the secret, if no secret file is given.
+	_secretEnv = "IFCGUID_SECRET"
+
+	_anonymizeSummary = "remove the node ids (MAC addresses) from the time-based GlobalIds of an IFC-SPF file"
+	_diffSummary      = "list the entities added, removed and changed between two IFC-SPF files, matched by GlobalId"
+	_forkSummary      = "replace every GlobalId of an IFC-SPF file with a keyed, deterministic new GlobalId"
+	_timeBasedSummary = "list the time-based GlobalIds of an IFC-SPF file, with their creation time and node id"
+	_unforkSummary    = "restore the original GlobalIds of a forked IFC-SPF file"
+)
+
+// command is a subcommand of the CLI.
+type command struct {
+	summary string
+	run     func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+}
+
+var _commands = map[string]command{
+	"anonymize": {
+		summary: _anonymizeSummary,
+		run:     runAnonymize,
+	},
+	"diff": {
+		summary: _diffSummary,
+		run:     runDiff,
+	},
+	"fork": {
+		summary: _forkSummary,
+		run: func(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+			return runFork("fork", _forkSummary, args, stdin, stdout, stderr)
+		},
+	},
+	"timebased": {
+		summary: _timeBasedSummary,
+		run:     runTimeBased,
+	},
+	"unfork": {
+		summary: _unforkSummary,
+		run: func(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+			return runFork("unfork", _unforkSummary, args, stdin, stdout, stderr)
+		},
+	},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the CLI and returns the exit code.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		usage(stderr)
+		return 2
+	}
+	cmd, ok := _commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "ifcguid: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+	if err := cmd.run(args[1:], stdin, stdout, stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 2
+		}
+		fmt.Fprintf(stderr, "ifcguid %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+// usage writes the list of commands.
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: ifcguid <command> [flags] [file]")
+	fmt.Fprintln(w, "\ncommands:")
+	names := make([]string, 0, len(_commands))
+	for name := range _commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-10s %s\n", name, _commands[name].summary)
+	}
+}
+
+// runFork runs the fork and unfork commands.
+func runFork(name, summary string, args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet(name, summary, stderr)
+	secretFile := fs.String("secret-file", "", "`file` with the secret; defaults to the "+_secretEnv+" environment variable")
+	output := fs.String("o", "", "output `file`; defaults to standard output")
+	mapping := fs.String("mapping", "", "write the old and new GlobalIds to this CSV `file`")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+
+	secret, err := readSecret(*secretFile)
+	if err != nil {
+		return err
+	}
+	forker, err := ifcguid.NewForker(secret)
+	if err != nil {
+		return err
+	}
+	convert := forker.Fork
+	if name == "unfork" {
+		convert = forker.Unfork
+	}
+	return rewrite(fs.Arg(0), *output, *mapping, name, stdin, stdout, convert)
+}
+
+// runAnonymize runs the anonymize command.
+func runAnonymize(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("anonymize", _anonymizeSummary, stderr)
+	keyFile := fs.String("key-file", "", "`file` with a key for reproducible results; defaults to a random key")
+	precision := fs.Duration("precision", 0, "round creation times down to a multiple of this `duration`, e.g. 24h")
+	output := fs.String("o", "", "output `file`; defaults to standard output")
+	mapping := fs.String("mapping", "", "write the old and new GlobalIds to this CSV `file`")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+	var key []byte
+	if *keyFile != "" {
+		var err error
+		if key, err = readSecret(*keyFile); err != nil {
+			return err
+		}
+	}
+	anonymizer, err := ifcguid.NewAnonymizer(key, *precision)
+	if err != nil {
+		return err
+	}
+	return rewrite(fs.Arg(0), *output, *mapping, "anonymize", stdin, stdout, anonymizer.Anonymize)
+}
+
+// runTimeBased runs the timebased command.
+func runTimeBased(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("timebased", _timeBasedSummary, stderr)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return fmt.Errorf("too many arguments")
+	}
+	in, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	cw := csv.NewWriter(stdout)
+	_ = cw.Write([]string{"Id", "Type", "GlobalId", "Time", "Node", "ClockSequence", "RandomNode"})
+	s := spf.NewScanner(in)
+	for s.Scan() {
+		e := s.Entity()
+		info, ok, err := ifcguid.InspectTimeBased(e.GlobalId)
+		if err != nil || !ok {
+			continue
+		}
+		_ = cw.Write([]string{
+			"#" + strconv.FormatUint(e.Id, 10), e.Type, e.GlobalId, info.Time.Format(time.RFC3339Nano),
+			info.Node.String(), strconv.Itoa(info.ClockSequence), strconv.FormatBool(info.Random),
+		})
+	}
+	if err := s.Err(); err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// runDiff runs the diff command.
+func runDiff(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("diff", _diffSummary, stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: ifcguid diff [flags] old-file new-file\n\n%s.\n\nflags:\n", _diffSummary)
+		fs.PrintDefaults()
+	}
+	asJson := fs.Bool("json", false, "write the changes as JSON instead of a summary")
+	ownerHistory := fs.Bool("owner-history", false, "count changes of the OwnerHistory attribute")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("two files are required")
+	}
+	oldFile, err := openInput(fs.Arg(0), stdin)
+	if err != nil {
+		return err
+	}
+	defer oldFile.Close()
+	newFile, err := openInput(fs.Arg(1), stdin)
+	if err != nil {
+		return err
+	}
+	defer newFile.Close()
+
+	d := spf.Differ{OwnerHistory: *ownerHistory}
+	report, err := d.Diff(oldFile, newFile)
+	if err != nil {
+		return err
+	}
+	if *asJson {
+		return report.WriteJSON(stdout)
+	}
+	return report.WriteSummary(stdout)
+}
+
+// newFlagSet returns a FlagSet for a command, with a usage message that includes its summary.
+func newFlagSet(name, summary string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: ifcguid %s [flags] [file]\n\n%s.\n\nflags:\n", name, summary)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// rewrite replaces the GlobalIds of the IFC-SPF file input with convert, and writes the result to output.
+// If input is an .ifczip archive, the output is an .ifczip archive too.
+// If mapping isn't empty, the replaced GlobalIds are written to that CSV file, with the given reason.
+func rewrite(input, output, mapping, reason string, stdin io.Reader, stdout io.Writer, convert func(string) (string, error)) error {
+	return writeOutput(output, stdout, func(w io.Writer) error {
+		if input == "" || input == "-" {
+			return rewriteSpf(stdin, w, mapping, reason, convert)
+		}
+		return ifczip.Rewrite(input, w, ifczip.FormatSpf, func(r io.ReadSeeker, w io.Writer) error {
+			return rewriteSpf(r, w, mapping, reason, convert)
+		})
+	})
+}
+
+// rewriteSpf replaces the GlobalIds of the IFC-SPF file read from in with convert, see rewrite.
+func rewriteSpf(in io.Reader, w io.Writer, mapping, reason string, convert func(string) (string, error)) error {
+	replace := spf.Remap(nil, convert)
+	if mapping == "" {
+		_, err := spf.Rewrite(in, w, replace)
+		return err
+	}
+	return writeOutput(mapping, nil, func(m io.Writer) error {
+		mw := spf.NewMappingWriter(m)
+		_, err := spf.Rewrite(in, w, func(e spf.Entity) (string, bool, error) {
+			newId, ok, err := replace(e)
+			if ok {
+				err = mw.Write(spf.Replacement{Entity: e, New: newId, Reason: reason})
+			}
+			return newId, ok, err
+		})
+		if err != nil {
+			return err
+		}
+		return mw.Flush()
+	})
+}
+
+// readSecret reads the secret from a file, or from the environment if name is empty.
+// Trailing line breaks are removed.
+func readSecret(name string) ([]byte, error) {
+	var secret []byte
+	if name != "" {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return nil, err
+		}
+		secret = data
+	} else {
+		secret = []byte(os.Getenv(_secretEnv))
+	}
+	secret = bytes.TrimRight(secret, "\r\n")
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("no secret: use -secret-file or set %s", _secretEnv)
+	}
+	return secret, nil
+}
+
+// openInput opens the named IFC-SPF file, or returns stdin if name is empty or "-".
+// The file may be an .ifczip archive.
+func openInput(name string, stdin io.Reader) (io.ReadCloser, error) {
+	if name == "" || name == "-" {
+		return io.NopCloser(stdin), nil
+	}
+	return ifczip.OpenFile(name, ifczip.FormatSpf)
+}
+
+// writeOutput calls write with the named file, or with stdout if name is empty.
+// The file is written through a temporary file, so it may be the input file,
+// and it is left unchanged if write fails.
+func writeOutput(name string, stdout io.Writer, write func(w io.Writer) error) error {
+	if name == "" {
+		return write(stdout)
+	}
+	return atomicfile.Write(name, write)
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _model = `ISO-10303-21;
+HEADER;
+FILE_SCHEMA(('IFC4'));
+ENDSEC;
+DATA;
+#5= IFCOWNERHISTORY(#1,#2,$,.NOCHANGE.,$,$,$,0);
+#10= IFCPROJECT('0YvctVUKr0kugbFTf53O9L',#5,'Project',$,$,$,$,(#20),#30);
+#42= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',#5,'Wall',$,$,#40,#41,'316435',$);
+ENDSEC;
+END-ISO-10303-21;
+`
+
+func Test_run_fork_and_unfork(t *testing.T) {
+	dir := t.TempDir()
+	secret := filepath.Join(dir, "secret.txt")
+	assert.NoError(t, os.WriteFile(secret, []byte("project secret\n"), 0o600))
+	mapping := filepath.Join(dir, "mapping.csv")
+
+	var forked, stderr bytes.Buffer
+	code := run([]string{"fork", "-secret-file", secret, "-mapping", mapping}, strings.NewReader(_model), &forked, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.NotContains(t, forked.String(), "0YvctVUKr0kugbFTf53O9L")
+	assert.NotContains(t, forked.String(), "2DWKyvjkf7PffFYiFUDNsy")
+	assert.Contains(t, forked.String(), "'0QCtZIMtX$UqHmypQKoxW3'", "same as ifcguid.Forker")
+	assert.Equal(t, len(_model), forked.Len())
+
+	data, err := os.ReadFile(mapping)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Equal(t, []string{
+		"Id,Type,Old,New,Reason",
+		"#10,IFCPROJECT,0YvctVUKr0kugbFTf53O9L," + lines[1][len("#10,IFCPROJECT,0YvctVUKr0kugbFTf53O9L,"):],
+		"#42,IFCWALL,2DWKyvjkf7PffFYiFUDNsy,0QCtZIMtX$UqHmypQKoxW3,fork",
+	}, lines)
+
+	// Forking is deterministic.
+	var again bytes.Buffer
+	assert.Equal(t, 0, run([]string{"fork", "-secret-file", secret}, strings.NewReader(_model), &again, &stderr))
+	assert.Equal(t, forked.String(), again.String())
+
+	// Unforking restores the original file, here read from a file and written to a file.
+	in := filepath.Join(dir, "forked.ifc")
+	out := filepath.Join(dir, "restored.ifc")
+	assert.NoError(t, os.WriteFile(in, forked.Bytes(), 0o644))
+	t.Setenv(_secretEnv, "project secret")
+	code = run([]string{"unfork", "-o", out, in}, nil, &bytes.Buffer{}, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	restored, err := os.ReadFile(out)
+	assert.NoError(t, err)
+	assert.Equal(t, _model, string(restored))
+}
+
+func Test_run_fork_ifczip(t *testing.T) {
+	dir := t.TempDir()
+	in := filepath.Join(dir, "model.ifczip")
+	out := filepath.Join(dir, "forked.ifczip")
+	f, err := os.Create(in)
+	assert.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("Model.ifc")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(_model))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+
+	t.Setenv(_secretEnv, "project secret")
+	var stderr bytes.Buffer
+	code := run([]string{"fork", "-o", out, in}, nil, &bytes.Buffer{}, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+
+	zr, err := zip.OpenReader(out)
+	assert.NoError(t, err)
+	defer zr.Close()
+	if assert.Len(t, zr.File, 1) {
+		assert.Equal(t, "Model.ifc", zr.File[0].Name)
+		r, err := zr.File[0].Open()
+		assert.NoError(t, err)
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Contains(t, string(data), "'0QCtZIMtX$UqHmypQKoxW3'")
+	}
+
+	var report bytes.Buffer
+	assert.Equal(t, 0, run([]string{"timebased", in}, nil, &report, &stderr), stderr.String())
+	assert.Equal(t, "Id,Type,GlobalId,Time,Node,ClockSequence,RandomNode\n", report.String())
+}
+
+func Test_run_fork_same_path(t *testing.T) {
+	dir := t.TempDir()
+	model := filepath.Join(dir, "model.ifc")
+	assert.NoError(t, os.WriteFile(model, []byte(_model), 0o644))
+	t.Setenv(_secretEnv, "project secret")
+
+	// The input is read completely before it is replaced.
+	var stderr bytes.Buffer
+	assert.Equal(t, 0, run([]string{"fork", "-o", model, model}, nil, &bytes.Buffer{}, &stderr), stderr.String())
+	forked, err := os.ReadFile(model)
+	assert.NoError(t, err)
+	assert.Len(t, forked, len(_model))
+	assert.Contains(t, string(forked), "'0QCtZIMtX$UqHmypQKoxW3'")
+
+	assert.Equal(t, 0, run([]string{"unfork", "-o", model, model}, nil, &bytes.Buffer{}, &stderr), stderr.String())
+	restored, err := os.ReadFile(model)
+	assert.NoError(t, err)
+	assert.Equal(t, _model, string(restored))
+
+	// A failed rewrite leaves the input unchanged.
+	invalid := strings.Replace(_model, "2DWKyvjkf7PffFYiFUDNsy", "2DWKyvjkf7PffFYiFUDNs!", 1)
+	assert.NoError(t, os.WriteFile(model, []byte(invalid), 0o644))
+	assert.Equal(t, 1, run([]string{"anonymize", "-o", model, model}, nil, &bytes.Buffer{}, &stderr))
+	data, err := os.ReadFile(model)
+	assert.NoError(t, err)
+	assert.Equal(t, invalid, string(data))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary files are left behind")
+}
+
+func Test_run_with_errors(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 2, run(nil, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "fork")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"nope"}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `unknown command "nope"`)
+
+	t.Setenv(_secretEnv, "")
+	stderr.Reset()
+	assert.Equal(t, 1, run([]string{"fork"}, strings.NewReader(_model), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "no secret")
+
+	t.Setenv(_secretEnv, "secret")
+	stderr.Reset()
+	invalid := strings.Replace(_model, "2DWKyvjkf7PffFYiFUDNsy", "2DWKyvjkf7PffFYiFUDNs!", 1)
+	out := filepath.Join(t.TempDir(), "out.ifc")
+	assert.Equal(t, 1, run([]string{"fork", "-o", out}, strings.NewReader(invalid), &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "#42")
+	assert.NoFileExists(t, out, "incomplete output is removed")
+
+	assert.Equal(t, 2, run([]string{"fork", "-h"}, nil, &stdout, &stderr))
+	assert.Equal(t, 1, run([]string{"fork", "a", "b"}, nil, &stdout, &stderr))
+}
+
+func Test_run_timebased_and_anonymize(t *testing.T) {
+	// 32Cgi0b1GHxBF8dslUpjX6 is the UUIDv1 example of RFC 9562.
+	model := strings.Replace(_model, "2DWKyvjkf7PffFYiFUDNsy", "32Cgi0b1GHxBF8dslUpjX6", 1)
+
+	var report, stderr bytes.Buffer
+	code := run([]string{"timebased"}, strings.NewReader(model), &report, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Equal(t, "Id,Type,GlobalId,Time,Node,ClockSequence,RandomNode\n"+
+		"#42,IFCWALL,32Cgi0b1GHxBF8dslUpjX6,2022-02-22T19:22:22Z,9f:6b:de:ce:d8:46,13256,true\n", report.String())
+
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key")
+	assert.NoError(t, os.WriteFile(key, []byte("key"), 0o600))
+	mapping := filepath.Join(dir, "mapping.csv")
+	var anonymized bytes.Buffer
+	code = run([]string{"anonymize", "-key-file", key, "-precision", "24h", "-mapping", mapping},
+		strings.NewReader(model), &anonymized, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.NotContains(t, anonymized.String(), "32Cgi0b1GHxBF8dslUpjX6")
+	assert.Contains(t, anonymized.String(), "0YvctVUKr0kugbFTf53O9L", "GlobalIds that aren't time-based are kept")
+
+	data, err := os.ReadFile(mapping)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if assert.Len(t, lines, 2) {
+		assert.True(t, strings.HasPrefix(lines[1], "#42,IFCWALL,32Cgi0b1GHxBF8dslUpjX6,"), lines[1])
+	}
+
+	report.Reset()
+	assert.Equal(t, 0, run([]string{"timebased"}, strings.NewReader(anonymized.String()), &report, &stderr))
+	assert.Contains(t, report.String(), ",2022-02-22T00:00:00Z,")
+	assert.NotContains(t, report.String(), "9f:6b:de:ce:d8:46")
+
+	assert.Equal(t, 1, run([]string{"anonymize", "-precision", "-1h"}, strings.NewReader(model), &bytes.Buffer{}, &stderr))
+}
+
+func Test_run_diff(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "old.ifc")
+	assert.NoError(t, os.WriteFile(old, []byte(_model), 0o644))
+	changed := strings.Replace(_model, "'Wall'", "'Wall 2'", 1)
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"diff", old, "-"}, strings.NewReader(changed), &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), "0 added, 0 removed, 1 changed, 1 unchanged\n")
+	assert.Contains(t, stdout.String(), "~ 2DWKyvjkf7PffFYiFUDNsy IFCWALL #42 -> #42 \"Wall 2\"\n")
+
+	stdout.Reset()
+	code = run([]string{"diff", "-json", old, "-"}, strings.NewReader(changed), &stdout, &stderr)
+	assert.Equal(t, 0, code, stderr.String())
+	assert.Contains(t, stdout.String(), `"globalId": "2DWKyvjkf7PffFYiFUDNsy"`)
+
+	assert.Equal(t, 1, run([]string{"diff", old}, nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "two files are required")
+}
@@ -0,0 +1,302 @@
+// Package cobie validates and normalizes the ExtIdentifier column of COBie deliverables.
+//
+// Every COBie sheet that describes model objects has ExtSystem, ExtObject and ExtIdentifier columns,
+// and ExtIdentifier should hold the IFC GUID of the object.
+// In practice it often contains UUIDs, Revit UniqueIds or values mangled by spreadsheet tools.
+//
+// The package reads COBie workbooks (.xlsx, see package xlsx) and COBie CSV files,
+// validates every ExtIdentifier with ifcguid.IsValid, converts convertible values to IFC GUIDs,
+// and writes a corrected copy plus a change log.
+package cobie
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/xlsx"
+)
+
+// Column names used by COBie.
+const (
+	ColumnName          = "Name"
+	ColumnExtSystem     = "ExtSystem"
+	ColumnExtObject     = "ExtObject"
+	ColumnExtIdentifier = "ExtIdentifier"
+)
+
+// _guidSchemes are the identifier schemes whose values are converted to IFC GUIDs.
+// Integers and handles are not converted, because they are ambiguous without knowing the ExtSystem.
+var _guidSchemes = map[string]bool{
+	ifcguid.SchemeNameIfcGuid:  true,
+	ifcguid.SchemeNameUuid:     true,
+	ifcguid.SchemeNameArchicad: true,
+	ifcguid.SchemeNameRevit:    true,
+	ifcguid.SchemeNameTekla:    true,
+}
+
+// Status is the result of checking a single ExtIdentifier.
+type Status int
+
+const (
+	// Valid means the ExtIdentifier is a valid IFC GUID.
+	Valid Status = iota
+	// Normalized means the ExtIdentifier was converted to an IFC GUID.
+	Normalized
+	// Invalid means the ExtIdentifier isn't an IFC GUID and couldn't be converted.
+	Invalid
+	// Missing means the ExtIdentifier is empty or "n/a".
+	Missing
+	// Mangled means the ExtIdentifier looks like a GlobalId that a spreadsheet tool read as a number,
+	// and the original can't be recovered with certainty, see ifcguid.InspectExcelValue.
+	Mangled
+)
+
+// SchemeNameExcel is the scheme reported for ExtIdentifiers that were recovered from spreadsheet damage,
+// e.g. a GlobalId wrapped in ="..." or prefixed with an apostrophe.
+const SchemeNameExcel = "excel"
+
+// ErrMangled is returned by NormalizeExtIdentifier for values that were damaged by a spreadsheet tool.
+var ErrMangled = errors.New("damaged by a spreadsheet tool")
+
+// String returns the name of the status.
+func (s Status) String() string {
+	switch s {
+	case Valid:
+		return "valid"
+	case Normalized:
+		return "normalized"
+	case Invalid:
+		return "invalid"
+	case Missing:
+		return "missing"
+	case Mangled:
+		return "mangled"
+	default:
+		return "Status(" + strconv.Itoa(int(s)) + ")"
+	}
+}
+
+// Entry is the result of checking the ExtIdentifier of a single row.
+type Entry struct {
+	// Sheet is the name of the COBie sheet, e.g. "Component".
+	Sheet string
+	// Row is the 1-based row number, as shown by spreadsheet tools.
+	Row int
+	// Name is the value of the Name column.
+	Name      string
+	ExtSystem string
+	ExtObject string
+	// Old is the original ExtIdentifier.
+	Old string
+	// New is the normalized ExtIdentifier; it equals Old unless Status is Normalized.
+	New string
+	// Scheme is the name of the identifier scheme that Old was converted from, if Status is Normalized.
+	Scheme string
+	Status Status
+	// Err explains why the ExtIdentifier is invalid.
+	Err error
+}
+
+// Report is the result of checking a COBie deliverable.
+type Report struct {
+	// Entries lists every row with an ExtIdentifier column, in sheet and row order.
+	Entries []Entry
+}
+
+// Count returns the number of entries with the given status.
+func (r *Report) Count(status Status) int {
+	n := 0
+	for _, e := range r.Entries {
+		if e.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// WriteLog writes the entries that were normalized, are invalid, mangled or missing to w, as CSV.
+func (r *Report) WriteLog(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"Sheet", "Row", ColumnName, ColumnExtSystem, ColumnExtObject, "Old", "New", "Scheme", "Status", "Error"})
+	for _, e := range r.Entries {
+		if e.Status == Valid {
+			continue
+		}
+		errText := ""
+		if e.Err != nil {
+			errText = e.Err.Error()
+		}
+		_ = cw.Write([]string{
+			e.Sheet, strconv.Itoa(e.Row), e.Name, e.ExtSystem, e.ExtObject, e.Old, e.New, e.Scheme, e.Status.String(), errText,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CheckWorkbook checks the ExtIdentifier of every row in every sheet of a COBie workbook.
+// If fix is true, normalized values are written back to the workbook; save it with xlsx.Workbook.Save.
+func CheckWorkbook(wb *xlsx.Workbook, fix bool) (*Report, error) {
+	report := &Report{}
+	for _, sheet := range wb.Sheets() {
+		entries, err := checkRows(sheet.Name, sheet.Rows(), func(row, col int, value string) error {
+			if !fix {
+				return nil
+			}
+			return sheet.SetCell(row, col, value)
+		})
+		if err != nil {
+			return nil, err
+		}
+		report.Entries = append(report.Entries, entries...)
+	}
+	return report, nil
+}
+
+// CheckCSV checks the ExtIdentifier of every row in a COBie CSV file, holding the given sheet.
+// If w is not nil, the CSV is written to w with normalized values.
+func CheckCSV(sheet string, r io.Reader, w io.Writer) (*Report, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := checkRows(sheet, rows, func(row, col int, value string) error {
+		rows[row][col] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if w != nil {
+		cw := csv.NewWriter(w)
+		if err := cw.WriteAll(rows); err != nil {
+			return nil, err
+		}
+	}
+	return &Report{Entries: entries}, nil
+}
+
+// NormalizeExtIdentifier converts an ExtIdentifier to an IFC GUID.
+// IFC GUIDs are returned as is. UUIDs (in any form), Revit UniqueIds, Tekla and Archicad GUIDs are converted.
+// GlobalIds that a spreadsheet tool kept as text with a wrapper, like ="..." or a leading apostrophe, are unwrapped.
+// It returns the IFC GUID and the name of the scheme that was used.
+//
+// Values that look like GlobalIds read as numbers, e.g. "2.00E+10" or a GlobalId without its leading zeros,
+// return an error wrapping ErrMangled that lists the GlobalIds they may have been.
+// Digits only values are never repaired, because they may as well be integer ids.
+func NormalizeExtIdentifier(value string) (string, string, error) {
+	value = strings.TrimSpace(value)
+	if err := ifcguid.IsValid(value); err == nil {
+		return value, ifcguid.SchemeNameIfcGuid, nil
+	}
+	if d := ifcguid.InspectExcelValue(value); d.Mangled {
+		if d.Recoverable() && !isDigits(value) {
+			return d.Candidates[0], SchemeNameExcel, nil
+		}
+		reason := d.Err
+		if reason == nil {
+			reason = fmt.Errorf("%q may be an integer", value)
+		}
+		return "", "", fmt.Errorf("%w: %v%s", ErrMangled, reason, candidates(d.Candidates))
+	}
+	for _, i := range ifcguid.Detect(value) {
+		if _guidSchemes[i.Scheme.Name()] {
+			return i.GlobalId, i.Scheme.Name(), nil
+		}
+	}
+	return "", "", fmt.Errorf("not an IFC GUID, and not convertible: %q", value)
+}
+
+// checkRows checks the rows of a sheet, whose first row holds the column names.
+// Sheets without an ExtIdentifier column are skipped. set is called for every normalized value.
+func checkRows(sheet string, rows [][]string, set func(row, col int, value string) error) ([]Entry, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	idCol, ok := columns[ColumnExtIdentifier]
+	if !ok {
+		return nil, nil
+	}
+	get := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+	var entries []Entry
+	for i, row := range rows[1:] {
+		if isBlank(row) {
+			continue
+		}
+		e := Entry{
+			Sheet:     sheet,
+			Row:       i + 2,
+			Name:      get(row, ColumnName),
+			ExtSystem: get(row, ColumnExtSystem),
+			ExtObject: get(row, ColumnExtObject),
+			Old:       get(row, ColumnExtIdentifier),
+		}
+		e.New = e.Old
+		switch trimmed := strings.TrimSpace(e.Old); {
+		case trimmed == "" || strings.EqualFold(trimmed, "n/a"):
+			e.Status = Missing
+		case ifcguid.IsValid(e.Old) == nil:
+			e.Status = Valid
+		default:
+			ifcGuid, scheme, err := NormalizeExtIdentifier(e.Old)
+			if errors.Is(err, ErrMangled) {
+				e.Status, e.Err = Mangled, err
+				break
+			}
+			if err != nil {
+				e.Status, e.Err = Invalid, err
+				break
+			}
+			e.Status, e.New, e.Scheme = Normalized, ifcGuid, scheme
+			if err := set(i+1, idCol, ifcGuid); err != nil {
+				return nil, err
+			}
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// candidates describes the GlobalIds that a mangled value may have been; long lists are shortened.
+func candidates(list []string) string {
+	const maxListed = 3
+	switch {
+	case len(list) == 0:
+		return ""
+	case len(list) <= maxListed:
+		return "; could be " + strings.Join(list, ", ")
+	default:
+		return fmt.Sprintf("; could be %s or %d others", strings.Join(list[:maxListed], ", "), len(list)-maxListed)
+	}
+}
+
+// isDigits reports whether s consists of decimal digits only.
+func isDigits(s string) bool {
+	return s != "" && strings.Trim(s, "0123456789") == ""
+}
+
+// isBlank reports whether all values of a row are empty.
+func isBlank(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,167 @@
+package cobie
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/woweh/ifcguid/xlsx"
+)
+
+var testComponents = [][]string{
+	{"Name", "CreatedBy", "ExtSystem", "ExtObject", "ExtIdentifier"},
+	{"Door-01", "a@example.com", "Autodesk Revit", "IfcDoor", "2DWKyvjkf7PffFYiFUDNsy"},
+	{"Door-02", "a@example.com", "Autodesk Revit", "IfcDoor", "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e"},
+	{"Door-03", "a@example.com", "Archicad", "IfcDoor", "{3085A8E4-61FD-4776-9FF1-1B24A646CA4F}"},
+	{"Door-04", "a@example.com", "Autodesk Revit", "IfcDoor", "2.00E+10"},
+	{"Door-05", "a@example.com", "Autodesk Revit", "IfcDoor", "n/a"},
+	{"", "", "", "", ""},
+}
+
+// testWorkbook returns a COBie workbook with an Instruction sheet (without ExtIdentifier) and a Component sheet.
+func testWorkbook(t *testing.T) *xlsx.Workbook {
+	sheetXml := func(rows [][]string) string {
+		var sb strings.Builder
+		sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+		for _, row := range rows {
+			sb.WriteString(`<row>`)
+			for _, v := range row {
+				sb.WriteString(`<c t="inlineStr" s="1"><is><t>` + v + `</t></is></c>`)
+			}
+			sb.WriteString(`</row>`)
+		}
+		sb.WriteString(`</sheetData></worksheet>`)
+		return sb.String()
+	}
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range []struct{ name, content string }{
+		{"xl/workbook.xml", `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>
+<sheet name="Instruction" sheetId="1" r:id="rId1"/><sheet name="Component" sheetId="2" r:id="rId2"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", `<Relationships>
+<Relationship Id="rId1" Target="worksheets/sheet1.xml"/><Relationship Id="rId2" Target="worksheets/sheet2.xml"/></Relationships>`},
+		{"xl/worksheets/sheet1.xml", sheetXml([][]string{{"Version"}, {"COBie 2.4"}})},
+		{"xl/worksheets/sheet2.xml", sheetXml(testComponents)},
+	} {
+		w, err := zw.Create(f.name)
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, f.content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	wb, err := xlsx.Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	return wb
+}
+
+func assertReport(t *testing.T, report *Report) {
+	if !assert.Len(t, report.Entries, 5) {
+		return
+	}
+	assert.Equal(t, 1, report.Count(Valid))
+	assert.Equal(t, 2, report.Count(Normalized))
+	assert.Equal(t, 1, report.Count(Mangled))
+	assert.Equal(t, 1, report.Count(Missing))
+
+	e := report.Entries[1]
+	assert.Equal(t, "Component", e.Sheet)
+	assert.Equal(t, 3, e.Row)
+	assert.Equal(t, "Door-02", e.Name)
+	assert.Equal(t, "IfcDoor", e.ExtObject)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", e.New)
+	assert.Equal(t, "revit", e.Scheme)
+
+	e = report.Entries[2]
+	assert.Equal(t, "0mXQZaOVr7Tf$n6oIcHifF", e.New)
+	assert.Equal(t, "uuid", e.Scheme)
+
+	e = report.Entries[3]
+	assert.Equal(t, Mangled, e.Status)
+	assert.Equal(t, "2.00E+10", e.New)
+	assert.ErrorIs(t, e.Err, ErrMangled)
+	assert.ErrorContains(t, e.Err, `the original notation of "2.00E+10" is lost; could be 0000000000020000000000, 0000000000000000002E10, 0000000000000000002e10 or 20 others`)
+}
+
+func Test_CheckWorkbook(t *testing.T) {
+	wb := testWorkbook(t)
+	report, err := CheckWorkbook(wb, false)
+	assert.NoError(t, err)
+	assertReport(t, report)
+	assert.Equal(t, "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e", wb.Sheet("Component").Cell(2, 4))
+
+	report, err = CheckWorkbook(wb, true)
+	assert.NoError(t, err)
+	assertReport(t, report)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", wb.Sheet("Component").Cell(2, 4))
+	assert.Equal(t, "0mXQZaOVr7Tf$n6oIcHifF", wb.Sheet("Component").Cell(3, 4))
+
+	var buf bytes.Buffer
+	assert.NoError(t, wb.Write(&buf))
+	reopened, err := xlsx.Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	report, err = CheckWorkbook(reopened, false)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, report.Count(Valid))
+	assert.Equal(t, 0, report.Count(Normalized))
+}
+
+func Test_CheckCSV(t *testing.T) {
+	var in, out bytes.Buffer
+	assert.NoError(t, csv.NewWriter(&in).WriteAll(testComponents))
+
+	report, err := CheckCSV("Component", &in, &out)
+	assert.NoError(t, err)
+	assertReport(t, report)
+
+	rows, err := csv.NewReader(&out).ReadAll()
+	assert.NoError(t, err)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", rows[2][4])
+	assert.Equal(t, "0mXQZaOVr7Tf$n6oIcHifF", rows[3][4])
+	assert.Equal(t, "Door-03", rows[3][0])
+
+	var log bytes.Buffer
+	assert.NoError(t, report.WriteLog(&log))
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "Component,3,Door-02,Autodesk Revit,IfcDoor,8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e,2DWKyvjkf7PffFYiFUDNsy,revit,normalized,", lines[1])
+}
+
+func Test_NormalizeExtIdentifier(t *testing.T) {
+	got, scheme, err := NormalizeExtIdentifier(" ID52A8B2C6-0000-0D51-3134-353338303231 ")
+	assert.NoError(t, err)
+	assert.Equal(t, "1IgBB6000DKJ4qDJCuC38n", got)
+	assert.Equal(t, "tekla", scheme)
+
+	_, _, err = NormalizeExtIdentifier("123456")
+	assert.Error(t, err, "integers are ambiguous without the ExtSystem")
+	assert.ErrorIs(t, err, ErrMangled)
+	assert.ErrorContains(t, err, `"123456" may be an integer; could be 0000000000000000123456`)
+
+	// Text wrappers that spreadsheet users add are removed.
+	got, scheme, err = NormalizeExtIdentifier(`="0000000000000000123456"`)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000000000123456", got)
+	assert.Equal(t, SchemeNameExcel, scheme)
+	got, scheme, err = NormalizeExtIdentifier("'2DWKyvjkf7PffFYiFUDNsy")
+	assert.NoError(t, err)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", got)
+	assert.Equal(t, SchemeNameExcel, scheme)
+
+	// Lost precision and notation can't be recovered.
+	_, _, err = NormalizeExtIdentifier("1234567890123450000000")
+	assert.NoError(t, err, "22 digits are a valid GlobalId")
+	_, _, err = NormalizeExtIdentifier("1.23457E+21")
+	assert.ErrorIs(t, err, ErrMangled)
+	_, _, err = NormalizeExtIdentifier("12345678901234500000")
+	assert.ErrorIs(t, err, ErrMangled)
+	assert.ErrorContains(t, err, "precision lost")
+
+	_, _, err = NormalizeExtIdentifier("not an id")
+	assert.Error(t, err)
+	assert.NotErrorIs(t, err, ErrMangled)
+}
@@ -0,0 +1,136 @@
+package ifcguid
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// _defaultConfidence is used for registered schemes that don't implement ConfidenceScorer.
+	_defaultConfidence = 0.5
+	// _normalizeThreshold is the minimum confidence the best interpretation needs to be accepted by Normalize.
+	_normalizeThreshold = 0.5
+	// _ambiguityFloor is the minimum confidence another interpretation needs to make the input ambiguous in Normalize.
+	_ambiguityFloor = 0.25
+)
+
+// Interpretation is one plausible reading of an identifier string.
+type Interpretation struct {
+	// Scheme is the identifier scheme that accepted the input.
+	Scheme IdScheme
+	// Confidence is a value between 0 and 1; higher values are more likely.
+	Confidence float64
+	// GlobalId is the IFC GUID the input converts to under this interpretation.
+	GlobalId string
+}
+
+// ConfidenceScorer can be implemented by an IdScheme to rank its interpretations in Detect.
+// Schemes that don't implement it get a neutral confidence of 0.5.
+type ConfidenceScorer interface {
+	// Confidence returns a value between 0 and 1 for a value that the scheme has already detected.
+	Confidence(value string) float64
+}
+
+// Detect returns all plausible interpretations of the identifier string s, ranked by confidence (highest first).
+//
+// Every registered scheme is asked to detect s, and every interpretation that converts to a valid IFC GUID is returned.
+// Leading and trailing white space is ignored.
+// Ambiguous inputs, like "1A" (an AutoCAD handle or an arbitrary string), yield several interpretations.
+func Detect(s string) []Interpretation {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	var result []Interpretation
+	for _, scheme := range Schemes() {
+		if !scheme.Detect(s) {
+			continue
+		}
+		u, err := scheme.ToUuid(s)
+		if err != nil {
+			continue
+		}
+		ifcGuid, err := FromUuid(u)
+		if err != nil {
+			continue
+		}
+		confidence := _defaultConfidence
+		if scorer, ok := scheme.(ConfidenceScorer); ok {
+			confidence = scorer.Confidence(s)
+		}
+		result = append(result, Interpretation{Scheme: scheme, Confidence: confidence, GlobalId: ifcGuid})
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Confidence > result[j].Confidence })
+	return result
+}
+
+// Normalize converts the identifier string s to an IFC GUID, if its interpretation is unambiguous.
+//
+// The best interpretation returned by Detect needs a confidence of at least 0.5,
+// and all other interpretations with a confidence of at least 0.25 must agree on the same IFC GUID.
+// For example, "123" is ambiguous (an integer, or a hexadecimal AutoCAD handle),
+// and so is "1A" (an AutoCAD handle, or an IFC GUID without its leading zeros, see SchemeBase64),
+// while a Revit UniqueId is not.
+func Normalize(s string) (string, error) {
+	i, err := Identify(s)
+	if err != nil {
+		return "", err
+	}
+	return i.GlobalId, nil
+}
+
+// Identify returns the interpretation of the identifier string s that Normalize uses, with its scheme.
+// It returns an error if s is unrecognized or ambiguous, see Normalize.
+func Identify(s string) (Interpretation, error) {
+	interpretations := Detect(s)
+	if len(interpretations) == 0 || interpretations[0].Confidence < _normalizeThreshold {
+		return Interpretation{}, fmt.Errorf("unrecognized identifier: %q", s)
+	}
+	var candidates []Interpretation
+	for _, i := range interpretations {
+		if i.Confidence >= _ambiguityFloor {
+			candidates = append(candidates, i)
+		}
+	}
+	for _, c := range candidates[1:] {
+		if c.GlobalId != candidates[0].GlobalId {
+			names := make([]string, len(candidates))
+			for i, c := range candidates {
+				names[i] = c.Scheme.Name()
+			}
+			return Interpretation{}, fmt.Errorf("ambiguous identifier %q: could be %s", s, strings.Join(names, ", "))
+		}
+	}
+	return candidates[0], nil
+}
+
+func (ifcGuidScheme) Confidence(string) float64 { return 0.95 }
+
+func (revitScheme) Confidence(string) float64 { return 1 }
+
+func (stringScheme) Confidence(string) float64 { return 0.1 }
+
+// Confidence is low, but high enough to make short hex handles and integers ambiguous in Normalize:
+// they are base-64 digits as well.
+func (base64Scheme) Confidence(string) float64 { return 0.3 }
+
+func (uuidScheme) Confidence(value string) float64 {
+	// The plain 32 hex character form carries less structure than the dashed forms.
+	if len(value) == 32 {
+		return 0.8
+	}
+	return 0.95
+}
+
+func (autoCadScheme) Confidence(value string) float64 {
+	// All-digit strings are more likely integers, but are still valid handles.
+	if strings.Trim(value, "0123456789") == "" {
+		return 0.5
+	}
+	return 0.6
+}
+
+func (int32Scheme) Confidence(string) float64 { return 0.6 }
+
+func (int64Scheme) Confidence(string) float64 { return 0.6 }
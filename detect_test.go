@@ -0,0 +1,146 @@
+package ifcguid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Detect(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantFirst string
+		wantAlso  []string
+	}{
+		{
+			name:      "IFC GUID",
+			input:     "2DWKyvjkf7PffFYiFUDNsy",
+			wantFirst: SchemeNameIfcGuid,
+		},
+		{
+			name:      "Braced GUID",
+			input:     "{01cf62c8-e9bc-bf88-0000-000000000005}",
+			wantFirst: SchemeNameUuid,
+		},
+		{
+			name:      "Revit UniqueId",
+			input:     "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e",
+			wantFirst: SchemeNameRevit,
+		},
+		{
+			name:      "Hex handle",
+			input:     "1A",
+			wantFirst: SchemeNameAutoCad,
+			wantAlso:  []string{SchemeNameBase64, SchemeNameString},
+		},
+		{
+			name:      "Decimal integer",
+			input:     " 123 ",
+			wantFirst: SchemeNameInt32,
+			wantAlso:  []string{SchemeNameInt64, SchemeNameAutoCad, SchemeNameBase64, SchemeNameString},
+		},
+		{
+			name:      "Free text",
+			input:     "Wall-01",
+			wantFirst: SchemeNameString,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Detect(tt.input)
+			if !assert.NotEmpty(t, got) {
+				return
+			}
+			var names []string
+			for _, i := range got {
+				names = append(names, i.Scheme.Name())
+				assert.NoError(t, IsValid(i.GlobalId))
+			}
+			assert.Equal(t, tt.wantFirst, names[0])
+			for _, want := range tt.wantAlso {
+				assert.Contains(t, names, want)
+			}
+			for i := 1; i < len(got); i++ {
+				assert.GreaterOrEqual(t, got[i-1].Confidence, got[i].Confidence)
+			}
+		})
+	}
+
+	assert.Empty(t, Detect(""))
+	assert.Empty(t, Detect("   "))
+}
+
+func Test_Normalize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "IFC GUID",
+			input: "2DWKyvjkf7PffFYiFUDNsy",
+			want:  "2DWKyvjkf7PffFYiFUDNsy",
+		},
+		{
+			name:  "UUID",
+			input: "01cf62c8-e9bc-bf88-0000-000000000005",
+			want:  "01psB8wRo$Y00000000005",
+		},
+		{
+			name:  "Revit UniqueId",
+			input: "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e",
+			want:  "2DWKyvjkf7PffFYiFUDNsy",
+		},
+		{
+			name:    "Ambiguous hex or base 64",
+			input:   "1A",
+			wantErr: true,
+		},
+		{
+			name:    "Ambiguous decimal or hex",
+			input:   "123",
+			wantErr: true,
+		},
+		{
+			name:    "Free text",
+			input:   "Wall-01",
+			wantErr: true,
+		},
+		{
+			name:    "Empty string",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Empty(t, got)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := Normalize("1A")
+	assert.ErrorContains(t, err, `ambiguous identifier "1A": could be autocad, base64`)
+}
+
+func Test_Identify(t *testing.T) {
+	i, err := Identify("8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e")
+	assert.NoError(t, err)
+	assert.Equal(t, SchemeRevit, i.Scheme)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", i.GlobalId)
+
+	_, err = Identify("123")
+	assert.ErrorContains(t, err, "ambiguous identifier")
+	_, err = Identify("Wall-01")
+	assert.EqualError(t, err, `unrecognized identifier: "Wall-01"`)
+}
@@ -13,9 +13,21 @@
 //   - Convert between IFC GUIDs and UUIDs (aka compression/decompression)
 //   - Validate Revit UniqueIDs
 //   - Convert Revit UniqueIDs to IFC GUIDs
+//   - Convert between IFC GUIDs and Tekla Structures GUIDs
+//   - Convert between IFC GUIDs and Archicad element GUIDs
+//   - Convert between IFC GUIDs and Speckle object ids
 //   - Convert between IFC GUIDs and AutoCAD handles
+//   - Convert between IFC GUIDs and Bentley iModel element ids
 //   - Convert between IFC GUIDs and integer representations
 //   - Convert arbitrary strings to and from IFC GUIDs
+//   - Convert between registered identifier schemes, including custom ones (see IdScheme)
+//   - Detect the format of arbitrary identifier strings and normalize them to IFC GUIDs
+//   - Export GlobalIds safely to spreadsheets, and repair GlobalIds damaged by spreadsheet tools
+//   - Find case-insensitive GlobalId collisions and estimate their risk
+//   - Convert IFC GUIDs to and from Crockford base 32, ULID text and hex
+//   - Format and parse all common GUID text forms, and print GlobalIds with fmt verbs (see GlobalId)
+//   - Derive new GlobalIds from existing ones with a secret key, and back (see Forker)
+//   - Inspect and anonymize GlobalIds created from time-based UUIDs (see InspectTimeBased and Anonymizer)
 //
 // Usage:
 //
@@ -0,0 +1,146 @@
+package ifcguid
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// _crockfordTable is the Crockford base 32 alphabet, also used by ULIDs.
+	_crockfordTable = `0123456789ABCDEFGHJKMNPQRSTVWXYZ`
+	// _base32Length is the number of base 32 characters needed for 128 bits.
+	_base32Length = 26
+)
+
+// ToBase32 converts an IFC GUID to Crockford base 32, in lower case, e.g. "01sxhchtdwqy40000000000005".
+//
+// Unlike IFC GUIDs, the result is case-insensitive and only contains letters and digits,
+// so it is safe to use in file names, URLs, shell scripts and Makefiles.
+// The encoding holds the same 128 bits as the UUID returned by ToUuid.
+func ToBase32(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(uuidToBase32(u)), nil
+}
+
+// FromBase32 converts a Crockford base 32 string to an IFC GUID.
+// Decoding is case-insensitive, hyphens are ignored, and 'I', 'L' and 'O' are read as '1', '1' and '0'.
+func FromBase32(s string) (string, error) {
+	u, err := base32ToUuid(s)
+	if err != nil {
+		return "", err
+	}
+	return FromUuid(u)
+}
+
+// ToUlid converts an IFC GUID to a ULID text representation (26 upper case Crockford base 32 characters).
+// The encoding holds the same 128 bits as the UUID returned by ToUuid.
+// Note that the timestamp part of the ULID is only meaningful if the GlobalId was created from a ULID.
+func ToUlid(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	return uuidToBase32(u), nil
+}
+
+// FromUlid converts a ULID text representation to an IFC GUID.
+func FromUlid(ulid string) (string, error) {
+	if len(ulid) != _base32Length {
+		return "", fmt.Errorf("the ULID must be %d characters long", _base32Length)
+	}
+	return FromBase32(ulid)
+}
+
+// ToHex converts an IFC GUID to 32 lower case hexadecimal characters, i.e. the UUID without hyphens.
+// Speckle object ids use the same form, see ToSpeckleId.
+func ToHex(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	return uuidToHex(u), nil
+}
+
+// FromHex converts 32 hexadecimal characters to an IFC GUID.
+func FromHex(s string) (string, error) {
+	u, err := hexToUuid(s)
+	if err != nil {
+		return "", err
+	}
+	return FromUuid(u)
+}
+
+// hexToUuid converts 32 hexadecimal characters to a UUID.
+func hexToUuid(s string) (uuid.UUID, error) {
+	if len(s) != 32 {
+		return uuid.Nil, fmt.Errorf("the hexadecimal string must be 32 characters long")
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("invalid hexadecimal string: %w", err)
+	}
+	return uuid.FromBytes(b)
+}
+
+// uuidToHex converts a UUID to 32 lower case hexadecimal characters.
+func uuidToHex(u uuid.UUID) string {
+	return hex.EncodeToString(u[:])
+}
+
+// uuidToBase32 converts a UUID to upper case Crockford base 32.
+func uuidToBase32(u uuid.UUID) string {
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+	chars := make([]byte, _base32Length)
+	for i := _base32Length - 1; i >= 0; i-- {
+		chars[i] = _crockfordTable[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(chars)
+}
+
+// base32ToUuid converts a Crockford base 32 string to a UUID.
+func base32ToUuid(s string) (uuid.UUID, error) {
+	s = strings.ReplaceAll(s, "-", "")
+	if len(s) != _base32Length {
+		return uuid.Nil, fmt.Errorf("the base 32 string must be %d characters long", _base32Length)
+	}
+	var hi, lo uint64
+	for i := 0; i < len(s); i++ {
+		v := crockfordValue(s[i])
+		if v < 0 {
+			return uuid.Nil, fmt.Errorf("invalid base 32 character: %q", s[i])
+		}
+		if i == 0 && v > 7 {
+			return uuid.Nil, fmt.Errorf("illegal base 32 string: it is greater than 128 bits")
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(v)
+	}
+	var u uuid.UUID
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
+
+// crockfordValue returns the value of a Crockford base 32 character, or -1 if it is invalid.
+func crockfordValue(c byte) int {
+	switch c {
+	case 'I', 'i', 'L', 'l':
+		return 1
+	case 'O', 'o':
+		return 0
+	}
+	if c >= 'a' && c <= 'z' {
+		c -= 'a' - 'A'
+	}
+	return strings.IndexByte(_crockfordTable, c)
+}
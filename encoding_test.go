@@ -0,0 +1,101 @@
+package ifcguid
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Base32_conversions(t *testing.T) {
+	for i := 0; i < 1000; i++ {
+		ifcGuid, err := New()
+		assert.NoError(t, err)
+
+		b32, err := ToBase32(ifcGuid)
+		assert.NoError(t, err)
+		assert.Len(t, b32, 26)
+		assert.Equal(t, strings.ToLower(b32), b32)
+
+		got, err := FromBase32(b32)
+		assert.NoError(t, err)
+		assert.Equal(t, ifcGuid, got)
+
+		got, err = FromBase32(strings.ToUpper(b32))
+		assert.NoError(t, err)
+		assert.Equal(t, ifcGuid, got)
+
+		ulid, err := ToUlid(ifcGuid)
+		assert.NoError(t, err)
+		assert.Equal(t, strings.ToUpper(b32), ulid)
+
+		got, err = FromUlid(ulid)
+		assert.NoError(t, err)
+		assert.Equal(t, ifcGuid, got)
+	}
+}
+
+func Test_Base32_known_values(t *testing.T) {
+	tests := []struct {
+		ifcGuid string
+		base32  string
+	}{
+		{"000000000000000000000Q", "0000000000000000000000000t"},
+		{"3$$$$$$$$$$$$$$$$$$$$$", "7zzzzzzzzzzzzzzzzzzzzzzzzz"},
+		{"01psB8wRo$Y00000000005", "01sxhchtdwqy40000000000005"},
+		{"1IgBB6000DKJ4qDJCuC38n", "2jn2scc0001n8k2d1n6cw30chh"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ifcGuid, func(t *testing.T) {
+			got, err := ToBase32(tt.ifcGuid)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.base32, got)
+
+			ifcGuid, err := FromBase32(tt.base32)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ifcGuid, ifcGuid)
+		})
+	}
+
+	// Crockford decoding rules: I and L are read as 1, O as 0, and hyphens are ignored.
+	got, err := FromBase32("00000000OO-0000000000-00000T")
+	assert.NoError(t, err)
+	assert.Equal(t, "000000000000000000000Q", got)
+	got, err = FromBase32("2JN2SCCOOOIN8K2DLN6CW3OCHH")
+	assert.NoError(t, err)
+	assert.Equal(t, "1IgBB6000DKJ4qDJCuC38n", got)
+}
+
+func Test_Base32_with_invalid_data(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"000000000000000000000000t",   // too short
+		"80000000000000000000000000",  // more than 128 bits
+		"0000000000000000000000000U",  // U is not in the alphabet
+		"0000000000000000000000000$",  // not in the alphabet
+		"000000000000000000000000000", // too long
+	} {
+		_, err := FromBase32(s)
+		assert.Error(t, err, s)
+	}
+	_, err := FromUlid("0000000000-000000000000000T")
+	assert.Error(t, err, "ULIDs don't contain hyphens")
+	_, err = ToBase32("invalid")
+	assert.Error(t, err)
+}
+
+func Test_Hex_conversions(t *testing.T) {
+	got, err := ToHex("01psB8wRo$Y00000000005")
+	assert.NoError(t, err)
+	assert.Equal(t, "01cf62c8e9bcbf880000000000000005", got)
+
+	ifcGuid, err := FromHex("01CF62C8E9BCBF880000000000000005")
+	assert.NoError(t, err)
+	assert.Equal(t, "01psB8wRo$Y00000000005", ifcGuid)
+
+	for _, s := range []string{"", "01cf62c8e9bcbf88000000000000000", "01cf62c8e9bcbf88000000000000000g"} {
+		_, err := FromHex(s)
+		assert.Error(t, err, s)
+	}
+}
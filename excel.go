@@ -0,0 +1,184 @@
+package ifcguid
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// _excelPrecision is the number of significant digits spreadsheet tools keep for numbers.
+	_excelPrecision = 15
+	// _excelMaxExponent is the largest decimal exponent of a double.
+	_excelMaxExponent = 308
+)
+
+var (
+	// _excelNumeric matches GlobalIds that spreadsheet tools read as numbers: digits, optionally in E notation.
+	_excelNumeric = regexp.MustCompile(`^[0-9]+([Ee][0-9]+)?$`)
+	// _excelScientific matches a number displayed in scientific notation, e.g. "2E+10" or "1.23457E+21".
+	_excelScientific = regexp.MustCompile(`^([0-9])(?:\.([0-9]+))?[Ee]\+?([0-9]+)$`)
+	// _excelDigits matches a number displayed without exponent.
+	_excelDigits = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// ExcelDamage describes a value that may be a GlobalId damaged by a spreadsheet tool, see InspectExcelValue.
+type ExcelDamage struct {
+	// Value is the inspected value.
+	Value string
+	// Mangled reports whether the value looks like a GlobalId that was read as a number.
+	Mangled bool
+	// Candidates lists the valid IFC GUIDs that the value may have been before it was damaged.
+	Candidates []string
+	// Err explains why the value can't be recovered, if it can't.
+	Err error
+}
+
+// Recoverable reports whether the original GlobalId is known, i.e. whether there is exactly one candidate.
+func (d ExcelDamage) Recoverable() bool {
+	return d.Err == nil && len(d.Candidates) == 1
+}
+
+// IsExcelRisky reports whether a spreadsheet tool like Excel would read the GlobalId as a number,
+// i.e. whether it consists of digits only, or of digits with a single 'E' or 'e' (scientific notation).
+// Such GlobalIds lose leading zeros and precision, or are reformatted, when a CSV file is opened.
+func IsExcelRisky(ifcGuid string) bool {
+	return _excelNumeric.MatchString(ifcGuid)
+}
+
+// ExcelSafe returns the GlobalId in a form that spreadsheet tools keep as text when reading a CSV file.
+// GlobalIds that IsExcelRisky are wrapped in a text formula, e.g. ="0123456789012345678901".
+// Other GlobalIds are returned unchanged.
+func ExcelSafe(ifcGuid string) string {
+	if IsExcelRisky(ifcGuid) {
+		return `="` + ifcGuid + `"`
+	}
+	return ifcGuid
+}
+
+// InspectExcelValue checks whether value is a GlobalId that was damaged by a spreadsheet tool,
+// and lists the GlobalIds it may have been.
+//
+// The following kinds of damage are recognized:
+//   - text wrappers, like a leading apostrophe or ="...", which are simply removed
+//   - trimmed leading zeros, e.g. "12345" for "0000000000000000012345"
+//   - scientific notation, e.g. "2.00E+10" for "000000000000000000002E10", "000000000000000000002e10",
+//     "00000000000000000020E9" or "0000000000020000000000"; these are never recoverable because the original notation is lost
+//   - lost precision, i.e. more than 15 significant digits; these are never recoverable
+//
+// Valid GlobalIds are returned with Mangled set to false and the value itself as the only candidate.
+func InspectExcelValue(value string) ExcelDamage {
+	d := ExcelDamage{Value: value}
+	text := unwrapExcelText(strings.TrimSpace(value))
+	if IsValid(text) == nil {
+		d.Mangled = text != value
+		d.Candidates = []string{text}
+		return d
+	}
+	switch {
+	case _excelDigits.MatchString(text):
+		// Numbers typed as digits are displayed as digits, so the original was digits too.
+		d.Mangled = true
+		d.Candidates = excelCandidates(text, 0, false)
+		if significant := len(strings.TrimRight(text, "0")); len(text) > _excelPrecision && significant <= _excelPrecision {
+			d.Err = fmt.Errorf("precision lost: %q has more than %d digits", text, _excelPrecision)
+		}
+	case _excelScientific.MatchString(text):
+		d.Mangled = true
+		m := _excelScientific.FindStringSubmatch(text)
+		exponent, err := strconv.Atoi(m[3])
+		if err != nil || exponent > _excelMaxExponent {
+			d.Err = fmt.Errorf("invalid exponent: %q", text)
+			return d
+		}
+		fraction := strings.TrimRight(m[2], "0")
+		digits := m[1] + fraction
+		if exponent < len(fraction) {
+			d.Err = fmt.Errorf("not an integer: %q", text)
+			return d
+		}
+		// Numbers typed in E notation keep a scientific number format, but long digit strings are displayed
+		// in scientific notation as well, so the original could have been either.
+		d.Candidates = excelCandidates(digits, exponent-len(fraction), true)
+		d.Err = fmt.Errorf("the original notation of %q is lost", text)
+	default:
+		d.Err = fmt.Errorf("not a damaged GlobalId: %q", value)
+		return d
+	}
+	if len(d.Candidates) == 0 {
+		d.Err = fmt.Errorf("no GlobalId matches %q", value)
+	} else if d.Err == nil && len(d.Candidates) > 1 {
+		d.Err = fmt.Errorf("ambiguous: %q could be any of %s", value, strings.Join(d.Candidates, ", "))
+	}
+	return d
+}
+
+// RepairExcelValue returns the original GlobalId of a value damaged by a spreadsheet tool, see InspectExcelValue.
+// It returns an error if the value isn't recoverable.
+func RepairExcelValue(value string) (string, error) {
+	d := InspectExcelValue(value)
+	if !d.Recoverable() {
+		return "", d.Err
+	}
+	return d.Candidates[0], nil
+}
+
+// RepairExcelValues repairs a list of values with RepairExcelValue.
+// It returns the repaired values, where values that can't be recovered are left unchanged,
+// and the damages of the values that can't be recovered.
+func RepairExcelValues(values []string) ([]string, []ExcelDamage) {
+	repaired := make([]string, len(values))
+	var unrecoverable []ExcelDamage
+	for i, v := range values {
+		d := InspectExcelValue(v)
+		if d.Recoverable() {
+			repaired[i] = d.Candidates[0]
+			continue
+		}
+		repaired[i] = v
+		unrecoverable = append(unrecoverable, d)
+	}
+	return repaired, unrecoverable
+}
+
+// unwrapExcelText removes the text wrappers that spreadsheet users add to keep a value as text.
+func unwrapExcelText(s string) string {
+	if strings.HasPrefix(s, `="`) && strings.HasSuffix(s, `"`) && len(s) >= 3 {
+		return s[2 : len(s)-1]
+	}
+	return strings.TrimPrefix(s, "'")
+}
+
+// excelCandidates returns all valid GlobalIds that a spreadsheet tool would read as the number digits × 10^exponent.
+// The GlobalId may have been written as digits only, or, if notation is true, in E notation with either case.
+func excelCandidates(digits string, exponent int, notation bool) []string {
+	var result []string
+	add := func(s string) {
+		if len(s) > 22 {
+			return
+		}
+		s = strings.Repeat("0", 22-len(s)) + s
+		if IsValid(s) == nil {
+			result = append(result, s)
+		}
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if digits == "" {
+		digits = "0"
+	}
+	if len(digits)+exponent <= 22 {
+		add(digits + strings.Repeat("0", exponent))
+	}
+	if !notation {
+		return result
+	}
+	// E notation: mantissa digits followed by j zeros, and exponent - j.
+	for j := 0; j <= exponent && len(digits)+j+2 <= 22; j++ {
+		mantissa := digits + strings.Repeat("0", j)
+		e := strconv.Itoa(exponent - j)
+		add(mantissa + "E" + e)
+		add(mantissa + "e" + e)
+	}
+	return result
+}
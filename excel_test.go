@@ -0,0 +1,134 @@
+package ifcguid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_ExcelSafe(t *testing.T) {
+	tests := []struct {
+		ifcGuid string
+		want    string
+	}{
+		{"0000000000000000000012", `="0000000000000000000012"`},
+		{"000000000000000002E100", `="000000000000000002E100"`},
+		{"000000000000000002e100", `="000000000000000002e100"`},
+		{"2DWKyvjkf7PffFYiFUDNsy", "2DWKyvjkf7PffFYiFUDNsy"},
+		{"000000000000000007MyqL", "000000000000000007MyqL"},
+		{"0000000000000000002EE1", "0000000000000000002EE1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ifcGuid, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExcelSafe(tt.ifcGuid))
+			assert.Equal(t, tt.want != tt.ifcGuid, IsExcelRisky(tt.ifcGuid))
+		})
+	}
+}
+
+func Test_InspectExcelValue(t *testing.T) {
+	tests := []struct {
+		name            string
+		value           string
+		wantMangled     bool
+		wantRecoverable bool
+		wantCandidates  []string
+	}{
+		{
+			name:            "Valid GlobalId",
+			value:           "2DWKyvjkf7PffFYiFUDNsy",
+			wantRecoverable: true,
+			wantCandidates:  []string{"2DWKyvjkf7PffFYiFUDNsy"},
+		},
+		{
+			name:            "Text formula",
+			value:           `="0000000000000000000012"`,
+			wantMangled:     true,
+			wantRecoverable: true,
+			wantCandidates:  []string{"0000000000000000000012"},
+		},
+		{
+			name:            "Leading apostrophe",
+			value:           "'0000000000000000000012",
+			wantMangled:     true,
+			wantRecoverable: true,
+			wantCandidates:  []string{"0000000000000000000012"},
+		},
+		{
+			name:            "Trimmed leading zeros",
+			value:           "12",
+			wantMangled:     true,
+			wantRecoverable: true,
+			wantCandidates:  []string{"0000000000000000000012"},
+		},
+		{
+			name:           "Lost precision",
+			value:          "1234567890123450000",
+			wantMangled:    true,
+			wantCandidates: []string{"0001234567890123450000"},
+		},
+		{
+			name:        "Too many digits",
+			value:       "12345678901234567890123",
+			wantMangled: true,
+		},
+		{
+			name:           "Scientific notation",
+			value:          "2.00E+3",
+			wantMangled:    true,
+			wantCandidates: []string{"0000000000000000002000", "00000000000000000002E3"},
+		},
+		{
+			name:        "Not an integer",
+			value:       "1.5E+0",
+			wantMangled: true,
+		},
+		{
+			name:  "Unrelated text",
+			value: "Door-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InspectExcelValue(tt.value)
+			assert.Equal(t, tt.wantMangled, got.Mangled)
+			assert.Equal(t, tt.wantRecoverable, got.Recoverable())
+			if tt.wantCandidates != nil {
+				assert.Subset(t, got.Candidates, tt.wantCandidates)
+			}
+			for _, c := range got.Candidates {
+				assert.NoError(t, IsValid(c))
+			}
+			if !tt.wantRecoverable {
+				assert.Error(t, got.Err)
+				_, err := RepairExcelValue(tt.value)
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func Test_InspectExcelValue_scientific_candidates(t *testing.T) {
+	got := InspectExcelValue("2.00E+3")
+	assert.False(t, got.Recoverable())
+	assert.ElementsMatch(t, []string{
+		"0000000000000000002000",
+		"00000000000000000002E3", "00000000000000000002e3",
+		"00000000000000000020E2", "00000000000000000020e2",
+		"00000000000000000200E1", "00000000000000000200e1",
+		"00000000000000002000E0", "00000000000000002000e0",
+	}, got.Candidates)
+}
+
+func Test_RepairExcelValues(t *testing.T) {
+	repaired, unrecoverable := RepairExcelValues([]string{"2DWKyvjkf7PffFYiFUDNsy", "12", "2.00E+10", " Door "})
+	assert.Equal(t, []string{"2DWKyvjkf7PffFYiFUDNsy", "0000000000000000000012", "2.00E+10", " Door "}, repaired)
+	if assert.Len(t, unrecoverable, 2) {
+		assert.Equal(t, "2.00E+10", unrecoverable[0].Value)
+		assert.True(t, unrecoverable[0].Mangled)
+		assert.Equal(t, " Door ", unrecoverable[1].Value)
+		assert.False(t, unrecoverable[1].Mangled)
+	}
+}
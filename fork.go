@@ -0,0 +1,61 @@
+package ifcguid
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// _forkContext separates the keys of a Forker from other uses of the same secret.
+const _forkContext = "ifcguid fork v1"
+
+// Forker derives new GlobalIds from existing ones with a secret key, e.g. when a model is copied into a new project.
+//
+// Fork is deterministic: forking a later revision of the same model with the same secret gives the same GlobalIds.
+// It is a keyed permutation of the 128 bits of the GlobalId, i.e. an AES block encryption with a key derived
+// from the secret with HMAC-SHA256. So distinct GlobalIds never collide after forking,
+// and Unfork gives the original GlobalId back to anyone who knows the secret.
+// Without the secret, the original GlobalIds can't be derived from the forked ones.
+type Forker struct {
+	block cipher.Block
+}
+
+// NewForker returns a Forker for the given secret, e.g. a project-specific passphrase.
+func NewForker(secret []byte) (*Forker, error) {
+	if len(secret) == 0 {
+		return nil, fmt.Errorf("the secret must not be empty")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(_forkContext))
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, err
+	}
+	return &Forker{block: block}, nil
+}
+
+// Fork returns the forked GlobalId of an IFC GUID.
+func (f *Forker) Fork(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	var forked uuid.UUID
+	f.block.Encrypt(forked[:], u[:])
+	return FromUuid(forked)
+}
+
+// Unfork returns the original GlobalId of a GlobalId returned by Fork with the same secret.
+func (f *Forker) Unfork(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	var original uuid.UUID
+	f.block.Decrypt(original[:], u[:])
+	return FromUuid(original)
+}
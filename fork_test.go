@@ -0,0 +1,62 @@
+package ifcguid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Forker(t *testing.T) {
+	f, err := NewForker([]byte("project secret"))
+	assert.NoError(t, err)
+	same, err := NewForker([]byte("project secret"))
+	assert.NoError(t, err)
+	other, err := NewForker([]byte("another secret"))
+	assert.NoError(t, err)
+
+	seen := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		ifcGuid, err := New()
+		assert.NoError(t, err)
+
+		forked, err := f.Fork(ifcGuid)
+		assert.NoError(t, err)
+		assert.NoError(t, IsValid(forked))
+		assert.NotEqual(t, ifcGuid, forked)
+		assert.False(t, seen[forked])
+		seen[forked] = true
+
+		again, err := same.Fork(ifcGuid)
+		assert.NoError(t, err)
+		assert.Equal(t, forked, again, "forking must be deterministic")
+
+		otherForked, err := other.Fork(ifcGuid)
+		assert.NoError(t, err)
+		assert.NotEqual(t, forked, otherForked)
+
+		original, err := f.Unfork(forked)
+		assert.NoError(t, err)
+		assert.Equal(t, ifcGuid, original)
+	}
+}
+
+func Test_Forker_known_value(t *testing.T) {
+	// Guards against accidental changes of the key derivation, which would break existing forks.
+	f, err := NewForker([]byte("project secret"))
+	assert.NoError(t, err)
+	forked, err := f.Fork("2DWKyvjkf7PffFYiFUDNsy")
+	assert.NoError(t, err)
+	assert.Equal(t, "0QCtZIMtX$UqHmypQKoxW3", forked)
+}
+
+func Test_Forker_with_invalid_data(t *testing.T) {
+	_, err := NewForker(nil)
+	assert.Error(t, err)
+
+	f, err := NewForker([]byte("secret"))
+	assert.NoError(t, err)
+	_, err = f.Fork("invalid")
+	assert.Error(t, err)
+	_, err = f.Unfork("invalid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,151 @@
+package ifcguid
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Style selects a text form of the UUID of an IFC GUID, see Format.
+// The N, D, B, P and X styles match the format specifiers of .NET's Guid.ToString.
+type Style int
+
+const (
+	// StyleD is the dashed form, e.g. "52a8b2c6-0000-0d51-3134-353338303231", like ToUuidString.
+	StyleD Style = iota
+	// StyleN is 32 hexadecimal digits, e.g. "52a8b2c600000d513134353338303231".
+	StyleN
+	// StyleB is the dashed form in braces, e.g. "{52a8b2c6-0000-0d51-3134-353338303231}".
+	StyleB
+	// StyleP is the dashed form in parentheses, e.g. "(52a8b2c6-0000-0d51-3134-353338303231)".
+	StyleP
+	// StyleX is the C structure form,
+	// e.g. "{0x52a8b2c6,0x0000,0x0d51,{0x31,0x34,0x35,0x33,0x38,0x30,0x32,0x31}}".
+	StyleX
+	// StyleUrn is the URN form of RFC 9562, e.g. "urn:uuid:52a8b2c6-0000-0d51-3134-353338303231".
+	StyleUrn
+	// StyleRegistry is the form used in the Windows registry and in Visual Studio project files,
+	// e.g. "{52A8B2C6-0000-0D51-3134-353338303231}".
+	StyleRegistry
+)
+
+// StyleUpper can be combined with any style to use upper case hexadecimal digits, e.g. StyleN|StyleUpper.
+// Prefixes, like "urn:uuid:" and "0x", stay in lower case.
+const StyleUpper Style = 1 << 8
+
+// _structForm matches the X style, with optional white space.
+var _structForm = regexp.MustCompile(`^\{0x([0-9a-f]{1,8}),0x([0-9a-f]{1,4}),0x([0-9a-f]{1,4}),\{` +
+	`0x([0-9a-f]{1,2}),0x([0-9a-f]{1,2}),0x([0-9a-f]{1,2}),0x([0-9a-f]{1,2}),` +
+	`0x([0-9a-f]{1,2}),0x([0-9a-f]{1,2}),0x([0-9a-f]{1,2}),0x([0-9a-f]{1,2})\}\}$`)
+
+// String returns the name of the style, e.g. "N" or "urn", with a "+upper" suffix for StyleUpper.
+func (s Style) String() string {
+	var name string
+	switch s &^ StyleUpper {
+	case StyleD:
+		name = "D"
+	case StyleN:
+		name = "N"
+	case StyleB:
+		name = "B"
+	case StyleP:
+		name = "P"
+	case StyleX:
+		name = "X"
+	case StyleUrn:
+		name = "urn"
+	case StyleRegistry:
+		name = "registry"
+	default:
+		return "Style(" + strconv.Itoa(int(s)) + ")"
+	}
+	if s&StyleUpper != 0 {
+		name += "+upper"
+	}
+	return name
+}
+
+// Format converts an IFC GUID to the given text form of its UUID.
+// Use FromUuidString to convert any of these forms back to an IFC GUID.
+func Format(ifcGuid string, style Style) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	return formatUuid(u, style)
+}
+
+// formatUuid returns the given text form of a UUID.
+func formatUuid(u uuid.UUID, style Style) (string, error) {
+	d := u.String()
+	var s string
+	switch style &^ StyleUpper {
+	case StyleD:
+		s = d
+	case StyleN:
+		s = strings.ReplaceAll(d, "-", "")
+	case StyleB:
+		s = "{" + d + "}"
+	case StyleP:
+		s = "(" + d + ")"
+	case StyleX:
+		s = fmt.Sprintf("{0x%x,0x%x,0x%x,{0x%02x,0x%02x,0x%02x,0x%02x,0x%02x,0x%02x,0x%02x,0x%02x}}",
+			u[0:4], u[4:6], u[6:8], u[8], u[9], u[10], u[11], u[12], u[13], u[14], u[15])
+		if style&StyleUpper != 0 {
+			return strings.ReplaceAll(strings.ToUpper(s), "0X", "0x"), nil
+		}
+		return s, nil
+	case StyleUrn:
+		s = "urn:uuid:" + d
+		if style&StyleUpper != 0 {
+			return "urn:uuid:" + strings.ToUpper(d), nil
+		}
+		return s, nil
+	case StyleRegistry:
+		return "{" + strings.ToUpper(d) + "}", nil
+	default:
+		return "", fmt.Errorf("unknown style: %v", style)
+	}
+	if style&StyleUpper != 0 {
+		s = strings.ToUpper(s)
+	}
+	return s, nil
+}
+
+// parseUuid parses any text form of a UUID that Format produces, in upper or lower case.
+func parseUuid(s string) (uuid.UUID, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 38 && s[0] == '(' && s[37] == ')' {
+		s = s[1:37]
+	}
+	if compact := strings.ToLower(strings.Join(strings.Fields(s), "")); strings.HasPrefix(compact, "{0x") {
+		return parseStructForm(compact)
+	}
+	return uuid.Parse(s)
+}
+
+// parseStructForm parses the X form of a UUID, in lower case and without white space.
+func parseStructForm(s string) (uuid.UUID, error) {
+	m := _structForm.FindStringSubmatch(s)
+	if m == nil {
+		return uuid.Nil, fmt.Errorf("invalid UUID structure form: %q", s)
+	}
+	var u uuid.UUID
+	put := func(b []byte, hex string) {
+		v, _ := strconv.ParseUint(hex, 16, 32)
+		for i := len(b) - 1; i >= 0; i-- {
+			b[i] = byte(v)
+			v >>= 8
+		}
+	}
+	put(u[0:4], m[1])
+	put(u[4:6], m[2])
+	put(u[6:8], m[3])
+	for i := 0; i < 8; i++ {
+		put(u[8+i:9+i], m[4+i])
+	}
+	return u, nil
+}
@@ -0,0 +1,77 @@
+package ifcguid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Format(t *testing.T) {
+	const ifcGuid = "1IgBB6000DKJ4qDJCuC38n"
+	tests := []struct {
+		style Style
+		want  string
+	}{
+		{StyleD, "52a8b2c6-0000-0d51-3134-353338303231"},
+		{StyleD | StyleUpper, "52A8B2C6-0000-0D51-3134-353338303231"},
+		{StyleN, "52a8b2c600000d513134353338303231"},
+		{StyleN | StyleUpper, "52A8B2C600000D513134353338303231"},
+		{StyleB, "{52a8b2c6-0000-0d51-3134-353338303231}"},
+		{StyleB | StyleUpper, "{52A8B2C6-0000-0D51-3134-353338303231}"},
+		{StyleP, "(52a8b2c6-0000-0d51-3134-353338303231)"},
+		{StyleP | StyleUpper, "(52A8B2C6-0000-0D51-3134-353338303231)"},
+		{StyleX, "{0x52a8b2c6,0x0000,0x0d51,{0x31,0x34,0x35,0x33,0x38,0x30,0x32,0x31}}"},
+		{StyleX | StyleUpper, "{0x52A8B2C6,0x0000,0x0D51,{0x31,0x34,0x35,0x33,0x38,0x30,0x32,0x31}}"},
+		{StyleUrn, "urn:uuid:52a8b2c6-0000-0d51-3134-353338303231"},
+		{StyleUrn | StyleUpper, "urn:uuid:52A8B2C6-0000-0D51-3134-353338303231"},
+		{StyleRegistry, "{52A8B2C6-0000-0D51-3134-353338303231}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style.String(), func(t *testing.T) {
+			got, err := Format(ifcGuid, tt.style)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+
+			back, err := FromUuidString(tt.want)
+			assert.NoError(t, err)
+			assert.Equal(t, ifcGuid, back)
+		})
+	}
+
+	_, err := Format(ifcGuid, Style(42))
+	assert.Error(t, err)
+	_, err = Format("invalid", StyleN)
+	assert.Error(t, err)
+}
+
+func Test_FromUuidString_with_all_forms(t *testing.T) {
+	valid := []string{
+		"URN:UUID:52a8b2c6-0000-0d51-3134-353338303231",
+		"{ 0x52a8b2c6, 0x0, 0xd51, { 0x31, 0x34, 0x35, 0x33, 0x38, 0x30, 0x32, 0x31 } }",
+		" (52a8b2c6-0000-0d51-3134-353338303231) ",
+	}
+	for _, s := range valid {
+		got, err := FromUuidString(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, "1IgBB6000DKJ4qDJCuC38n", got, s)
+	}
+
+	invalid := []string{
+		"",
+		"(52a8b2c6-0000-0d51-3134-353338303231",
+		"{0x52a8b2c6,0x0000,0x0d51,{0x31,0x34,0x35,0x33,0x38,0x30,0x32}}",
+		"{0x52a8b2c6f,0x0000,0x0d51,{0x31,0x34,0x35,0x33,0x38,0x30,0x32,0x31}}",
+		"{0x52a8b2c6,0x0000,0x0d51,{0x31,0x34,0x35,0x33,0x38,0x30,0x32,0x131}}",
+	}
+	for _, s := range invalid {
+		_, err := FromUuidString(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func Test_Style_String(t *testing.T) {
+	assert.Equal(t, "N", StyleN.String())
+	assert.Equal(t, "urn+upper", (StyleUrn | StyleUpper).String())
+	assert.Equal(t, "Style(42)", Style(42).String())
+}
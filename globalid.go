@@ -0,0 +1,86 @@
+package ifcguid
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+)
+
+// GlobalId is an IFC GUID, e.g. "1IgBB6000DKJ4qDJCuC38n".
+//
+// It implements fmt.Formatter, with the following verbs:
+//   - %s and %v: the IFC GUID
+//   - %q: the IFC GUID in double quotes
+//   - %x and %X: the 32 hexadecimal digits of the UUID, in lower or upper case (the N style of Format)
+//   - %+v: the IFC GUID and the UUID, e.g. "1IgBB6000DKJ4qDJCuC38n (52a8b2c6-0000-0d51-3134-353338303231)"
+//
+// Width and alignment flags are applied to the whole result, e.g. "%-24s".
+type GlobalId string
+
+// ParseGlobalId parses an IFC GUID, or a UUID in any form accepted by FromUuidString.
+func ParseGlobalId(s string) (GlobalId, error) {
+	if IsValid(s) == nil {
+		return GlobalId(s), nil
+	}
+	ifcGuid, err := FromUuidString(s)
+	if err != nil {
+		return "", fmt.Errorf("the given string is neither an IFC GUID nor a UUID: %v", s)
+	}
+	return GlobalId(ifcGuid), nil
+}
+
+// String returns the IFC GUID.
+func (g GlobalId) String() string {
+	return string(g)
+}
+
+// IsValid returns an error if the GlobalId isn't a valid IFC GUID, see IsValid.
+func (g GlobalId) IsValid() error {
+	return IsValid(string(g))
+}
+
+// Uuid converts the GlobalId to a UUID, see ToUuid.
+func (g GlobalId) Uuid() (uuid.UUID, error) {
+	return ToUuid(string(g))
+}
+
+// In returns the given text form of the UUID of the GlobalId, see Format.
+func (g GlobalId) In(style Style) (string, error) {
+	return Format(string(g), style)
+}
+
+// Format implements fmt.Formatter, see GlobalId.
+func (g GlobalId) Format(f fmt.State, verb rune) {
+	var text string
+	switch verb {
+	case 's', 'q':
+		text = string(g)
+	case 'v':
+		text = string(g)
+		if f.Flag('+') {
+			if d, err := Format(string(g), StyleD); err == nil {
+				text += " (" + d + ")"
+			} else {
+				text += " (invalid)"
+			}
+		}
+	case 'x', 'X':
+		style := StyleN
+		if verb == 'X' {
+			style |= StyleUpper
+		}
+		n, err := Format(string(g), style)
+		if err != nil {
+			fmt.Fprintf(f, "%%!%c(invalid GlobalId=%s)", verb, string(g))
+			return
+		}
+		text = n
+	default:
+		fmt.Fprintf(f, "%%!%c(ifcguid.GlobalId=%s)", verb, string(g))
+		return
+	}
+	if verb != 'q' {
+		verb = 's'
+	}
+	fmt.Fprintf(f, fmt.FormatString(f, verb), text)
+}
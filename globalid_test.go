@@ -0,0 +1,65 @@
+package ifcguid
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_GlobalId_Format(t *testing.T) {
+	g := GlobalId("1IgBB6000DKJ4qDJCuC38n")
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"%s", "1IgBB6000DKJ4qDJCuC38n"},
+		{"%v", "1IgBB6000DKJ4qDJCuC38n"},
+		{"%q", `"1IgBB6000DKJ4qDJCuC38n"`},
+		{"%x", "52a8b2c600000d513134353338303231"},
+		{"%X", "52A8B2C600000D513134353338303231"},
+		{"%+v", "1IgBB6000DKJ4qDJCuC38n (52a8b2c6-0000-0d51-3134-353338303231)"},
+		{"[%24s]", "[  1IgBB6000DKJ4qDJCuC38n]"},
+		{"[%-24s]", "[1IgBB6000DKJ4qDJCuC38n  ]"},
+		{"%d", "%!d(ifcguid.GlobalId=1IgBB6000DKJ4qDJCuC38n)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			assert.Equal(t, tt.want, fmt.Sprintf(tt.format, g))
+		})
+	}
+
+	invalid := GlobalId("invalid")
+	assert.Equal(t, "invalid", fmt.Sprintf("%s", invalid))
+	assert.Equal(t, "invalid (invalid)", fmt.Sprintf("%+v", invalid))
+	assert.Equal(t, "%!x(invalid GlobalId=invalid)", fmt.Sprintf("%x", invalid))
+	assert.Equal(t, "[invalid]", fmt.Sprint([]GlobalId{invalid}))
+}
+
+func Test_ParseGlobalId(t *testing.T) {
+	for _, s := range []string{
+		"1IgBB6000DKJ4qDJCuC38n",
+		"52a8b2c6-0000-0d51-3134-353338303231",
+		"52A8B2C600000D513134353338303231",
+		"{52A8B2C6-0000-0D51-3134-353338303231}",
+		"urn:uuid:52a8b2c6-0000-0d51-3134-353338303231",
+		"{0x52a8b2c6,0x0000,0x0d51,{0x31,0x34,0x35,0x33,0x38,0x30,0x32,0x31}}",
+	} {
+		g, err := ParseGlobalId(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, GlobalId("1IgBB6000DKJ4qDJCuC38n"), g, s)
+	}
+
+	_, err := ParseGlobalId("not a GlobalId")
+	assert.Error(t, err)
+
+	g := GlobalId("1IgBB6000DKJ4qDJCuC38n")
+	assert.NoError(t, g.IsValid())
+	u, err := g.Uuid()
+	assert.NoError(t, err)
+	assert.Equal(t, "52a8b2c6-0000-0d51-3134-353338303231", u.String())
+	p, err := g.In(StyleP)
+	assert.NoError(t, err)
+	assert.Equal(t, "(52a8b2c6-0000-0d51-3134-353338303231)", p)
+}
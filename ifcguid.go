@@ -256,9 +256,10 @@ func FromUuid(u uuid.UUID) (string, error) {
 }
 
 // FromUuidString converts a UUID string s to an IFC GUID.
-// See the uuid.Parse() documentation for supported UUID forms.
+// It accepts every form produced by Format, in upper or lower case: the N, D, B, P and X forms of .NET,
+// URNs ("urn:uuid:...") and the registry form, see Style.
 func FromUuidString(s string) (string, error) {
-	u, err := uuid.Parse(s)
+	u, err := parseUuid(s)
 	if err != nil {
 		return "", err
 	}
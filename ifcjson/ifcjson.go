@@ -0,0 +1,439 @@
+// Package ifcjson reads and rewrites the identifiers of the JSON serializations of IFC:
+// ifcJSON, and IFCX, the JSON format of IFC5 (alpha).
+//
+// In ifcJSON, objects carry their GlobalId in a globalId member, and refer to other objects with a ref member.
+// Some exporters write the 22-character GlobalId, others an expanded UUID:
+//
+//	{"type": "IfcWall", "globalId": "2DWKyvjkf7PffFYiFUDNsy", "name": "Basic Wall"}
+//	{"type": "IfcBuildingStorey", "ref": "93791d5d-5beb-437b-b8ec-2f1f0ba4bf3b"}
+//
+// In IFCX, nodes are identified by UUID-based paths, and refer to other nodes by path in their children and inherits:
+//
+//	{"path": "93791d5d-5beb-437b-b8ec-2f1f0ba4bf3b", "children": {"My_Wall": "a3c1d1a4-7e6e-4b3a-a8a3-4f0b2a9d8c1e"}}
+//
+// The Scanner reads a file as a stream with encoding/json and returns every identifier,
+// normalized to a 22-character GlobalId with ifcguid.FromUuidString. ToCompact and ToExpanded convert
+// between the two representations, and Rewrite replaces identifiers in place, e.g. to expand all GlobalIds
+// of an ifcJSON file with Expand. All other bytes of the file are copied unchanged.
+//
+// Usage:
+//
+//	s := ifcjson.NewScanner(file)
+//	for s.Scan() {
+//		id := s.Identifier()
+//		fmt.Println(id.Kind, id.Pointer, id.GlobalId)
+//	}
+//	if err := s.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+//	// Write all identifiers as UUIDs
+//	_, err := ifcjson.Rewrite(in, out, ifcjson.Expand)
+package ifcjson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/woweh/ifcguid"
+)
+
+// _pointerEscaper escapes member names in JSON Pointers.
+var _pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// Kind is the kind of an identifier.
+type Kind int
+
+const (
+	// KindGlobalId is the globalId of an ifcJSON object.
+	KindGlobalId Kind = iota
+	// KindRef is the ref of an ifcJSON object, i.e. a reference to the object with that globalId.
+	KindRef
+	// KindPath is a UUID in the path of an IFCX node.
+	KindPath
+	// KindChild is a UUID in the children or inherits of an IFCX node, i.e. a reference to the node with that path.
+	KindChild
+)
+
+// String returns the name of the kind, e.g. "globalId".
+func (k Kind) String() string {
+	switch k {
+	case KindGlobalId:
+		return "globalId"
+	case KindRef:
+		return "ref"
+	case KindPath:
+		return "path"
+	case KindChild:
+		return "child"
+	}
+	return "Kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Identifier is an identifier in an ifcJSON or IFCX file.
+type Identifier struct {
+	// Kind is the kind of the identifier.
+	Kind Kind
+	// Value is the identifier as written in the file, e.g. "93791d5d-5beb-437b-b8ec-2f1f0ba4bf3b".
+	// For IFCX paths with several segments, it is the segment with the UUID.
+	Value string
+	// GlobalId is the identifier as a 22-character GlobalId, or empty if Value isn't valid, see Err.
+	GlobalId string
+	// Err is the reason why Value isn't a valid GlobalId or UUID.
+	// It is only set for the globalId and ref of ifcJSON objects; IFCX path segments that aren't UUIDs are skipped.
+	Err error
+	// Type is the type member of the JSON object that holds the identifier, e.g. "IfcWall",
+	// if it comes before the identifier, as ifcJSON exporters write it. It is empty for IFCX.
+	Type string
+	// Pointer is the JSON Pointer (RFC 6901) of the JSON string that holds the identifier, e.g. "/data/3/globalId".
+	Pointer string
+	// Offset is the byte offset of Value in the file, or -1 if the JSON string uses escapes,
+	// in which case Rewrite can't replace the identifier.
+	Offset int64
+}
+
+// frame is an object or array that the Scanner is in.
+type frame struct {
+	object bool
+	// key is the current member name of an object.
+	key string
+	// expectKey reports whether the next string of an object is a member name.
+	expectKey bool
+	// index is the current index of an array.
+	index int
+	// typ is the value of the type member of an object.
+	typ string
+}
+
+// Scanner reads the identifiers of an ifcJSON or IFCX file one by one.
+type Scanner struct {
+	d     *json.Decoder
+	rec   *recorder
+	stack []frame
+	// onToken, if set, is called after every token with the raw bytes since the previous token,
+	// and the identifiers in the token. It is used by Rewrite.
+	onToken func(raw []byte, ids []Identifier) error
+
+	pending    []Identifier
+	identifier Identifier
+	err        error
+	done       bool
+}
+
+// NewScanner returns a Scanner that reads from r.
+func NewScanner(r io.Reader) *Scanner {
+	rec := &recorder{r: bufio.NewReaderSize(r, 64*1024)}
+	d := json.NewDecoder(rec)
+	d.UseNumber()
+	return &Scanner{d: d, rec: rec}
+}
+
+// Scan advances the Scanner to the next identifier, which is then available through Identifier.
+// It returns false at the end of the input or after an error, see Err.
+func (s *Scanner) Scan() bool {
+	for len(s.pending) == 0 {
+		if s.done || s.err != nil {
+			return false
+		}
+		s.next()
+	}
+	s.identifier, s.pending = s.pending[0], s.pending[1:]
+	return true
+}
+
+// Identifier returns the identifier found by the last call to Scan.
+func (s *Scanner) Identifier() Identifier {
+	return s.identifier
+}
+
+// Err returns the first error encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// next reads the next token, and queues its identifiers.
+func (s *Scanner) next() {
+	tok, err := s.d.Token()
+	if err == io.EOF {
+		s.done = true
+		if len(s.stack) > 0 {
+			s.err = io.ErrUnexpectedEOF
+			return
+		}
+		s.err = s.flush(s.rec.buf.Len(), nil)
+		return
+	}
+	if err != nil {
+		s.err = err
+		return
+	}
+	end := int(s.d.InputOffset() - s.rec.start)
+	var ids []Identifier
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{', '[':
+			s.stack = append(s.stack, frame{object: t == '{', expectKey: t == '{'})
+		default:
+			s.stack = s.stack[:len(s.stack)-1]
+			s.advance()
+		}
+	case string:
+		top := s.top()
+		if top != nil && top.object && top.expectKey {
+			top.key = t
+			top.expectKey = false
+			break
+		}
+		if top != nil && top.object {
+			ids = s.identifiers(t, s.rec.buf.Bytes()[:end])
+			if top.key == "type" && !s.inReferences() {
+				top.typ = t
+			}
+		}
+		s.advance()
+	default:
+		s.advance()
+	}
+	if s.err = s.flush(end, ids); s.err == nil {
+		s.pending = append(s.pending, ids...)
+	}
+}
+
+// top returns the innermost object or array, or nil at the top level.
+func (s *Scanner) top() *frame {
+	if len(s.stack) == 0 {
+		return nil
+	}
+	return &s.stack[len(s.stack)-1]
+}
+
+// advance moves past a value of the innermost object or array.
+func (s *Scanner) advance() {
+	if top := s.top(); top != nil {
+		if top.object {
+			top.expectKey = true
+		} else {
+			top.index++
+		}
+	}
+}
+
+// pointer returns the JSON Pointer of the current value.
+func (s *Scanner) pointer() string {
+	var b strings.Builder
+	for _, f := range s.stack {
+		b.WriteByte('/')
+		if f.object {
+			b.WriteString(_pointerEscaper.Replace(f.key))
+		} else {
+			b.WriteString(strconv.Itoa(f.index))
+		}
+	}
+	return b.String()
+}
+
+// identifiers returns the identifiers in the string value of the current object member.
+// raw holds the bytes since the previous token, which end with the JSON string.
+func (s *Scanner) identifiers(value string, raw []byte) []Identifier {
+	top := s.top()
+	offset := int64(-1)
+	if q := bytes.IndexByte(raw, '"'); q >= 0 && string(raw[q+1:len(raw)-1]) == value {
+		offset = s.rec.start + int64(q) + 1
+	}
+	id := Identifier{Value: value, Type: top.typ, Pointer: s.pointer(), Offset: offset}
+
+	switch {
+	case top.key == "globalId" || top.key == "ref":
+		id.Kind = KindRef
+		if top.key == "globalId" {
+			id.Kind = KindGlobalId
+		}
+		id.GlobalId, id.Err = ToCompact(value)
+		return []Identifier{id}
+	case top.key == "path":
+		id.Kind = KindPath
+	case s.inReferences():
+		id.Kind = KindChild
+	default:
+		return nil
+	}
+
+	// IFCX paths are UUIDs, optionally followed by the names of nested children, e.g. "93791d5d-.../My_Wall".
+	var ids []Identifier
+	start := 0
+	for _, segment := range strings.Split(value, "/") {
+		if ifcGuid, err := ifcguid.FromUuidString(segment); err == nil {
+			segmentId := id
+			segmentId.Value = segment
+			segmentId.GlobalId = ifcGuid
+			if offset >= 0 {
+				segmentId.Offset = offset + int64(start)
+			}
+			ids = append(ids, segmentId)
+		}
+		start += len(segment) + 1
+	}
+	return ids
+}
+
+// inReferences reports whether the innermost object is the children or inherits of an IFCX node.
+func (s *Scanner) inReferences() bool {
+	if len(s.stack) < 2 {
+		return false
+	}
+	parent := s.stack[len(s.stack)-2]
+	return parent.object && (parent.key == "children" || parent.key == "inherits")
+}
+
+// flush passes the recorded bytes up to end to onToken, and discards them.
+func (s *Scanner) flush(end int, ids []Identifier) error {
+	var err error
+	if s.onToken != nil {
+		err = s.onToken(s.rec.buf.Bytes()[:end], ids)
+	}
+	s.rec.discard(end)
+	return err
+}
+
+// recorder is an io.Reader that keeps the bytes it has read, until they are discarded.
+type recorder struct {
+	r io.Reader
+	// buf holds the bytes read since the last discard.
+	buf bytes.Buffer
+	// start is the offset of the first byte in buf.
+	start int64
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.buf.Write(p[:n])
+	return n, err
+}
+
+// discard discards the first n recorded bytes.
+func (r *recorder) discard(n int) {
+	r.buf.Next(n)
+	r.start += int64(n)
+	if r.buf.Len() == 0 {
+		r.buf.Reset()
+	}
+}
+
+// ToCompact converts an identifier in any representation, i.e. a GlobalId or any UUID form accepted by
+// ifcguid.FromUuidString, to a 22-character GlobalId.
+func ToCompact(value string) (string, error) {
+	if ifcguid.IsValid(value) == nil {
+		return value, nil
+	}
+	ifcGuid, err := ifcguid.FromUuidString(value)
+	if err != nil {
+		return "", fmt.Errorf("neither a GlobalId nor a UUID: %q", value)
+	}
+	return ifcGuid, nil
+}
+
+// ToExpanded converts an identifier in any representation to a UUID in lower case,
+// in the form of `xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx`, as IFCX uses.
+func ToExpanded(value string) (string, error) {
+	ifcGuid, err := ToCompact(value)
+	if err != nil {
+		return "", err
+	}
+	return ifcguid.ToUuidString(ifcGuid)
+}
+
+// Replacer returns the new value of an identifier, or false to keep it, see Rewrite.
+type Replacer func(id Identifier) (newValue string, ok bool, err error)
+
+// Rewrite copies the ifcJSON or IFCX file read from r to w, and replaces identifiers as decided by replace.
+//
+// The Replacer is called for every identifier, in file order, including the invalid ones.
+// New values must be GlobalIds or UUIDs, see ToCompact. All other bytes of the file are copied unchanged.
+// Rewrite returns the number of replaced identifiers.
+func Rewrite(r io.Reader, w io.Writer, replace Replacer) (int, error) {
+	bw := bufio.NewWriter(w)
+	s := NewScanner(r)
+	count := 0
+	s.onToken = func(raw []byte, ids []Identifier) error {
+		pos := 0
+		for _, id := range ids {
+			newValue, ok, err := replace(id)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if _, err := ToCompact(newValue); err != nil {
+				return fmt.Errorf("%s: invalid new value: %w", id.Pointer, err)
+			}
+			if id.Offset < 0 {
+				return fmt.Errorf("%s: can't replace an identifier with escapes", id.Pointer)
+			}
+			from := int(id.Offset - s.rec.start)
+			bw.Write(raw[pos:from])
+			bw.WriteString(newValue)
+			pos = from + len(id.Value)
+			count++
+		}
+		_, err := bw.Write(raw[pos:])
+		return err
+	}
+	for s.Scan() {
+	}
+	if err := s.Err(); err != nil {
+		return count, err
+	}
+	return count, bw.Flush()
+}
+
+// Compact is a Replacer that writes every valid globalId and ref of an ifcJSON file as a 22-character GlobalId.
+// IFCX paths, children and inherits are kept, since IFCX addresses nodes by UUID.
+func Compact(id Identifier) (string, bool, error) {
+	if id.Kind != KindGlobalId && id.Kind != KindRef {
+		return "", false, nil
+	}
+	return id.GlobalId, id.Err == nil && id.GlobalId != id.Value, nil
+}
+
+// Expand is a Replacer that writes every valid identifier as a UUID, see ToExpanded.
+func Expand(id Identifier) (string, bool, error) {
+	if id.Err != nil {
+		return "", false, nil
+	}
+	expanded, err := ifcguid.ToUuidString(id.GlobalId)
+	return expanded, expanded != id.Value, err
+}
+
+// Remap returns a Replacer that replaces every valid identifier with convert(GlobalId),
+// e.g. with the Fork method of an ifcguid.Forker, keeping its representation:
+// GlobalIds are written as GlobalIds, and UUIDs as UUIDs in the form of ToExpanded.
+// Identifiers that convert returns unchanged aren't counted as replaced.
+func Remap(convert func(ifcGuid string) (string, error)) Replacer {
+	return func(id Identifier) (string, bool, error) {
+		if id.Err != nil {
+			return "", false, nil
+		}
+		newId, err := convert(id.GlobalId)
+		if err != nil {
+			return "", false, fmt.Errorf("%s: %w", id.Pointer, err)
+		}
+		if newId == id.GlobalId {
+			return "", false, nil
+		}
+		if id.Value == id.GlobalId {
+			return newId, true, nil
+		}
+		expanded, err := ToExpanded(newId)
+		if err != nil {
+			return "", false, fmt.Errorf("%s: %w", id.Pointer, err)
+		}
+		return expanded, true, nil
+	}
+}
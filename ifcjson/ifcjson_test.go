@@ -0,0 +1,227 @@
+package ifcjson
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/woweh/ifcguid"
+)
+
+const _ifcJsonFile = `{
+  "type": "ifcJSON",
+  "version": "0.0.1",
+  "schemaIdentifier": "IFC4",
+  "data": [
+    {
+      "type": "IfcBuildingStorey",
+      "globalId": "93791d5d-5beb-437b-b8ec-2f1f0ba4bf3b",
+      "name": "Level 1",
+      "elevation": 0.0
+    },
+    {
+      "type": "IfcWall",
+      "globalId": "2DWKyvjkf7PffFYiFUDNsy",
+      "name": "Wall \"A\"",
+      "containedIn": {"type": "IfcBuildingStorey", "ref": "93791d5d-5beb-437b-b8ec-2f1f0ba4bf3b"}
+    },
+    {"type": "IfcWindow", "globalId": "invalid", "isExternal": true, "tags": [null, 1, "globalId"]}
+  ]
+}
+`
+
+const _ifcxFile = `{
+  "header": {"id": "example", "ifcxVersion": "ifcx_alpha"},
+  "data": [
+    {
+      "path": "93791d5d-5beb-437b-b8ec-2f1f0ba4bf3b",
+      "children": {"My_Wall": "a3c1d1a4-7e6e-4b3a-a8a3-4f0b2a9d8c1e", "Removed": null}
+    },
+    {
+      "path": "a3c1d1a4-7e6e-4b3a-a8a3-4f0b2a9d8c1e/Window",
+      "inherits": {"type": "0a5e2f9c-1c39-4b6b-b6a7-16c7f0e4b0a1"},
+      "attributes": {"bsi::ifc::class": {"code": "IfcWall"}}
+    }
+  ]
+}
+`
+
+func scanAll(t *testing.T, data string) []Identifier {
+	s := NewScanner(strings.NewReader(data))
+	var ids []Identifier
+	for s.Scan() {
+		ids = append(ids, s.Identifier())
+	}
+	assert.NoError(t, s.Err())
+	return ids
+}
+
+func Test_Scanner_ifcJson(t *testing.T) {
+	ids := scanAll(t, _ifcJsonFile)
+	if !assert.Len(t, ids, 4) {
+		return
+	}
+	storey, err := ifcguid.FromUuidString("93791d5d-5beb-437b-b8ec-2f1f0ba4bf3b")
+	assert.NoError(t, err)
+
+	assert.Equal(t, KindGlobalId, ids[0].Kind)
+	assert.Equal(t, "IfcBuildingStorey", ids[0].Type)
+	assert.Equal(t, storey, ids[0].GlobalId)
+	assert.Equal(t, "/data/0/globalId", ids[0].Pointer)
+	assert.Equal(t, ids[0].Value, _ifcJsonFile[ids[0].Offset:ids[0].Offset+int64(len(ids[0].Value))])
+
+	assert.Equal(t, KindGlobalId, ids[1].Kind)
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", ids[1].GlobalId)
+	assert.Equal(t, "IfcWall", ids[1].Type)
+
+	assert.Equal(t, KindRef, ids[2].Kind)
+	assert.Equal(t, storey, ids[2].GlobalId)
+	assert.Equal(t, "/data/1/containedIn/ref", ids[2].Pointer)
+
+	assert.Equal(t, "/data/2/globalId", ids[3].Pointer)
+	assert.Error(t, ids[3].Err)
+	assert.Empty(t, ids[3].GlobalId)
+}
+
+func Test_Scanner_ifcx(t *testing.T) {
+	ids := scanAll(t, _ifcxFile)
+	if !assert.Len(t, ids, 4) {
+		return
+	}
+	assert.Equal(t, KindPath, ids[0].Kind)
+	assert.Equal(t, "/data/0/path", ids[0].Pointer)
+	assert.Equal(t, KindChild, ids[1].Kind)
+	assert.Equal(t, "/data/0/children/My_Wall", ids[1].Pointer)
+	assert.Equal(t, ids[1].GlobalId, ids[2].GlobalId)
+	assert.Equal(t, KindPath, ids[2].Kind)
+	assert.Equal(t, "a3c1d1a4-7e6e-4b3a-a8a3-4f0b2a9d8c1e", ids[2].Value)
+	assert.Equal(t, KindChild, ids[3].Kind)
+	assert.Equal(t, "/data/1/inherits/type", ids[3].Pointer)
+	for _, id := range ids {
+		assert.NoError(t, id.Err)
+		assert.Empty(t, id.Type)
+		assert.Equal(t, id.Value, _ifcxFile[id.Offset:id.Offset+int64(len(id.Value))])
+	}
+}
+
+func Test_Scanner_with_invalid_data(t *testing.T) {
+	s := NewScanner(strings.NewReader(`{"data": [{"globalId": "2DWKyvjkf7PffFYiFUDNsy"}`))
+	for s.Scan() {
+	}
+	assert.Error(t, s.Err())
+
+	ids := scanAll(t, `{"globalId": "\u0032DWKyvjkf7PffFYiFUDNsy", "a~b/c": {"ref": "2DWKyvjkf7PffFYiFUDNsy"}}`)
+	if assert.Len(t, ids, 2) {
+		assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", ids[0].GlobalId)
+		assert.Equal(t, int64(-1), ids[0].Offset)
+		assert.Equal(t, "/a~0b~1c/ref", ids[1].Pointer)
+	}
+}
+
+func Test_ToCompact_ToExpanded(t *testing.T) {
+	tests := []struct {
+		value    string
+		compact  string
+		expanded string
+	}{
+		{"2DWKyvjkf7PffFYiFUDNsy", "2DWKyvjkf7PffFYiFUDNsy", "8d814f39-b6ea-4766-9a4f-8ac3de357dbc"},
+		{"8d814f39-b6ea-4766-9a4f-8ac3de357dbc", "2DWKyvjkf7PffFYiFUDNsy", "8d814f39-b6ea-4766-9a4f-8ac3de357dbc"},
+		{"{8D814F39-B6EA-4766-9A4F-8AC3DE357DBC}", "2DWKyvjkf7PffFYiFUDNsy", "8d814f39-b6ea-4766-9a4f-8ac3de357dbc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			compact, err := ToCompact(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.compact, compact)
+			expanded, err := ToExpanded(tt.value)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expanded, expanded)
+		})
+	}
+	_, err := ToCompact("invalid")
+	assert.Error(t, err)
+	_, err = ToExpanded("")
+	assert.Error(t, err)
+}
+
+func Test_Rewrite(t *testing.T) {
+	var expanded bytes.Buffer
+	count, err := Rewrite(strings.NewReader(_ifcJsonFile), &expanded, Expand)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, strings.Replace(_ifcJsonFile, `"2DWKyvjkf7PffFYiFUDNsy"`, `"8d814f39-b6ea-4766-9a4f-8ac3de357dbc"`, 1), expanded.String())
+
+	var compact bytes.Buffer
+	count, err = Rewrite(strings.NewReader(expanded.String()), &compact, Compact)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	ids := scanAll(t, compact.String())
+	for _, id := range ids[:3] {
+		assert.Equal(t, id.GlobalId, id.Value)
+	}
+
+	for name, replace := range map[string]Replacer{"Expand": Expand, "Compact": Compact} {
+		var unchanged bytes.Buffer
+		count, err = Rewrite(strings.NewReader(_ifcxFile), &unchanged, replace)
+		assert.NoError(t, err, name)
+		assert.Zero(t, count, "%s keeps the UUIDs of IFCX nodes", name)
+		assert.Equal(t, _ifcxFile, unchanged.String(), name)
+	}
+
+	_, err = Rewrite(strings.NewReader(_ifcxFile), &bytes.Buffer{}, func(Identifier) (string, bool, error) {
+		return "invalid", true, nil
+	})
+	assert.ErrorContains(t, err, "/data/0/path")
+	_, err = Rewrite(strings.NewReader(`{"globalId": "\u0032DWKyvjkf7PffFYiFUDNsy"}`), &bytes.Buffer{}, Expand)
+	assert.ErrorContains(t, err, "escapes")
+}
+
+func Test_Remap(t *testing.T) {
+	forker, err := ifcguid.NewForker([]byte("project secret"))
+	assert.NoError(t, err)
+
+	var forked bytes.Buffer
+	count, err := Rewrite(strings.NewReader(_ifcxFile), &forked, Remap(forker.Fork))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	before := scanAll(t, _ifcxFile)
+	after := scanAll(t, forked.String())
+	if assert.Len(t, after, len(before)) {
+		for i := range before {
+			want, err := forker.Fork(before[i].GlobalId)
+			assert.NoError(t, err)
+			assert.Equal(t, want, after[i].GlobalId)
+			assert.Len(t, after[i].Value, 36)
+		}
+	}
+	assert.Contains(t, forked.String(), "/Window")
+
+	var restored bytes.Buffer
+	_, err = Rewrite(strings.NewReader(forked.String()), &restored, Remap(forker.Unfork))
+	assert.NoError(t, err)
+	assert.Equal(t, _ifcxFile, restored.String())
+}
+
+func Test_Kind_String(t *testing.T) {
+	assert.Equal(t, "globalId", KindGlobalId.String())
+	assert.Equal(t, "child", KindChild.String())
+	assert.Equal(t, "Kind(9)", Kind(9).String())
+}
+
+func Test_Rewrite_large_input(t *testing.T) {
+	var b strings.Builder
+	b.WriteString(`{"type": "ifcJSON", "data": [`)
+	for i := 0; i < 50_000; i++ {
+		if i > 0 {
+			b.WriteString(",\n")
+		}
+		b.WriteString(`{"type": "IfcWall", "globalId": "2DWKyvjkf7PffFYiFUDNsy", "name": "Wall"}`)
+	}
+	b.WriteString("]}\n")
+	var out bytes.Buffer
+	count, err := Rewrite(strings.NewReader(b.String()), &out, Expand)
+	assert.NoError(t, err)
+	assert.Equal(t, 50_000, count)
+	assert.Equal(t, strings.ReplaceAll(b.String(), "2DWKyvjkf7PffFYiFUDNsy", "8d814f39-b6ea-4766-9a4f-8ac3de357dbc"), out.String())
+}
@@ -0,0 +1,156 @@
+package ifcxml
+
+import (
+	"io"
+
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/ifczip"
+	"github.com/woweh/ifcguid/internal/guidcheck"
+)
+
+// Duplicate is a GlobalId used by more than one element of a file.
+type Duplicate struct {
+	// GlobalId is the shared GlobalId.
+	GlobalId string
+	// Elements lists the elements that use the GlobalId, in file order.
+	Elements []Element
+	// Rule is the name of the rule that suppressed the duplicate, if any.
+	Rule string
+}
+
+// Invalid is an element with a GlobalId that isn't a valid IFC GUID.
+type Invalid struct {
+	Element
+	// Err is the error returned by ifcguid.IsValid.
+	Err error
+}
+
+// Rule suppresses known-benign duplicates, see Checker.
+type Rule struct {
+	// Name identifies the rule in reports.
+	Name string
+	// Types suppresses duplicates whose elements all have one of these types, e.g. "IfcRelAssociatesMaterial".
+	// Types are compared case-insensitively.
+	Types []string
+	// Match, if set, suppresses duplicates it returns true for.
+	// A duplicate is suppressed if it matches Types or Match.
+	Match func(d Duplicate) bool
+}
+
+// DefaultRules are the rules used by Check and Repair. They match the default rules of the spf package.
+var DefaultRules = []Rule{
+	{
+		Name:  "revit-material-associations",
+		Types: []string{"IfcRelAssociatesMaterial"},
+	},
+}
+
+// Report is the result of checking the GlobalIds of an ifcXML file.
+type Report struct {
+	// Elements is the number of elements with a GlobalId.
+	Elements int
+	// Duplicates lists the GlobalIds used by more than one element, ordered by first use.
+	Duplicates []Duplicate
+	// Suppressed lists the duplicates suppressed by a rule, ordered by first use.
+	Suppressed []Duplicate
+	// Invalid lists the elements with an invalid GlobalId, in file order.
+	Invalid []Invalid
+}
+
+// Broken reports whether the file has duplicate or invalid GlobalIds, ignoring suppressed duplicates.
+func (r *Report) Broken() bool {
+	return len(r.Duplicates) > 0 || len(r.Invalid) > 0
+}
+
+// WriteLog writes the duplicate, suppressed and invalid GlobalIds as CSV, one line per element.
+func (r *Report) WriteLog(w io.Writer) error {
+	lw := guidcheck.NewLogWriter(w)
+	write := func(problem string, e Element, rule, errText string) {
+		lw.Write(problem, e.GlobalId, e.Id, e.Type, e.Name, e.Line, rule, errText)
+	}
+	for _, d := range r.Duplicates {
+		for _, e := range d.Elements {
+			write("duplicate", e, "", "")
+		}
+	}
+	for _, d := range r.Suppressed {
+		for _, e := range d.Elements {
+			write("suppressed", e, d.Rule, "")
+		}
+	}
+	for _, inv := range r.Invalid {
+		write("invalid", inv.Element, "", inv.Err.Error())
+	}
+	return lw.Flush()
+}
+
+// Checker finds duplicate and invalid GlobalIds in ifcXML files.
+type Checker struct {
+	// Rules suppress known-benign duplicates.
+	Rules []Rule
+}
+
+// Check checks the GlobalIds of the ifcXML file read from r, using DefaultRules.
+func Check(r io.Reader) (*Report, error) {
+	c := Checker{Rules: DefaultRules}
+	return c.Check(r)
+}
+
+// CheckFile checks the GlobalIds of the ifcXML file with the given name, using DefaultRules.
+// The file may be an .ifczip archive, see ifczip.OpenFile.
+func CheckFile(name string) (*Report, error) {
+	f, err := ifczip.OpenFile(name, ifczip.FormatXml)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Check(f)
+}
+
+// Check checks the GlobalIds of the ifcXML file read from r.
+// Every GlobalId is validated with ifcguid.IsValid, and compared case-sensitively to the others.
+func (c *Checker) Check(r io.Reader) (*Report, error) {
+	s := NewScanner(r)
+	report := &Report{}
+	var index guidcheck.Index
+	var elements []Element
+	for s.Scan() {
+		e := s.Element()
+		report.Elements++
+		if err := ifcguid.IsValid(e.GlobalId); err != nil {
+			report.Invalid = append(report.Invalid, Invalid{Element: e, Err: err})
+		}
+		index.Add(e.GlobalId)
+		elements = append(elements, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	for _, g := range index.Duplicates() {
+		d := Duplicate{GlobalId: g.GlobalId}
+		for _, i := range g.Elements {
+			d.Elements = append(d.Elements, elements[i])
+		}
+		if rule, ok := c.suppress(d); ok {
+			d.Rule = rule
+			report.Suppressed = append(report.Suppressed, d)
+		} else {
+			report.Duplicates = append(report.Duplicates, d)
+		}
+	}
+	return report, nil
+}
+
+// suppress returns the name of the first rule that suppresses d.
+func (c *Checker) suppress(d Duplicate) (string, bool) {
+	types := make([]string, len(d.Elements))
+	for i, e := range d.Elements {
+		types[i] = e.Type
+	}
+	for _, rule := range c.Rules {
+		if (rule.Match != nil && rule.Match(d)) || guidcheck.AllTypes(rule.Types, types) {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
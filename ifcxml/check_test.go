@@ -0,0 +1,75 @@
+package ifcxml
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// _duplicatesFile uses namespace prefixes, attributes in any order and quoted either way, entity-encoded values,
+// GlobalIds in the value of other attributes, and GlobalId attributes of other namespaces, like ifcXML exporters do.
+const _duplicatesFile = `<?xml version="1.0" encoding="UTF-8"?>
+<ifc:ifcXML xmlns:ifc="http://standards.buildingsmart.org/IFC/RELEASE/IFC4/ADD2_TC1/ifcXML" xmlns:ext="urn:example:ext">
+  <ifc:IfcWall id="i10" GlobalId="2DWKyvjkf7PffFYiFUDNsy" Name="Wall 1"/>
+  <ifc:IfcWall Name="Wall 2" GlobalId='2DWKyvjkf7PffFYiFUDNs&#121;' id="i11"/>
+  <IfcWindow xmlns="http://standards.buildingsmart.org/IFC/RELEASE/IFC4/ADD2_TC1/ifcXML"
+      Description='Copy of GlobalId="2DWKyvjkf7PffFYiFUDNsy"' GlobalId="4DWKyvjkf7PffFYiFUDNsy" id="i13" Name="Window &amp; Frame"/>
+  <ifc:IfcDoor id="i14" ext:GlobalId="2DWKyvjkf7PffFYiFUDNsy" GlobalId = "0mXQZaOVr7Tf$n6oIcHifF"/>
+  <ifc:IfcRelAssociatesMaterial id="i20" GlobalId="3vB2YO$MX4xv5uCqZZG05x"/>
+  <ifc:IfcRelAssociatesMaterial id="i21" GlobalId="3vB2YO$MX4xv5uCqZZG05x"/>
+</ifc:ifcXML>
+`
+
+func Test_Check(t *testing.T) {
+	report, err := Check(strings.NewReader(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.True(t, report.Broken())
+	assert.Equal(t, 6, report.Elements)
+	if assert.Len(t, report.Duplicates, 1, "GlobalIds are compared after decoding, and other namespaces are ignored") {
+		d := report.Duplicates[0]
+		assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", d.GlobalId)
+		if assert.Len(t, d.Elements, 2) {
+			assert.Equal(t, "i10", d.Elements[0].Id)
+			assert.Equal(t, "i11", d.Elements[1].Id)
+			assert.Equal(t, "IfcWall", d.Elements[1].Type, "without the namespace prefix")
+			assert.Equal(t, "Wall 2", d.Elements[1].Name)
+		}
+	}
+	if assert.Len(t, report.Suppressed, 1) {
+		assert.Equal(t, "revit-material-associations", report.Suppressed[0].Rule)
+	}
+	if assert.Len(t, report.Invalid, 1) {
+		assert.Equal(t, "i13", report.Invalid[0].Id)
+		assert.Equal(t, "Window & Frame", report.Invalid[0].Name)
+	}
+
+	var log bytes.Buffer
+	assert.NoError(t, report.WriteLog(&log))
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	assert.Len(t, lines, 1+2+2+1)
+	assert.Equal(t, "duplicate,2DWKyvjkf7PffFYiFUDNsy,i11,IfcWall,Wall 2,4,,", lines[2])
+	assert.Equal(t, "suppressed,3vB2YO$MX4xv5uCqZZG05x,i20,IfcRelAssociatesMaterial,,8,revit-material-associations,", lines[3])
+}
+
+func Test_Checker_rules(t *testing.T) {
+	c := Checker{}
+	report, err := c.Check(strings.NewReader(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.Len(t, report.Duplicates, 2)
+	assert.Empty(t, report.Suppressed)
+
+	c.Rules = []Rule{{Name: "walls", Types: []string{"ifcwall"}}, {Name: "all", Match: func(Duplicate) bool { return true }}}
+	report, err = c.Check(strings.NewReader(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.Empty(t, report.Duplicates)
+	if assert.Len(t, report.Suppressed, 2) {
+		assert.Equal(t, "walls", report.Suppressed[0].Rule)
+		assert.Equal(t, "all", report.Suppressed[1].Rule)
+	}
+	assert.True(t, report.Broken(), "invalid GlobalIds aren't suppressed")
+
+	_, err = Check(strings.NewReader(`<ifcXML><IfcWall GlobalId="2DWKyvjkf7PffFYiFUDNsy"></ifcXML>`))
+	assert.Error(t, err)
+}
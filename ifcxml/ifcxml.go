@@ -0,0 +1,300 @@
+// Package ifcxml reads and rewrites the GlobalIds of ifcXML files (ISO 10303-28, e.g. IFC4 ifcXML).
+//
+// In ifcXML, GlobalIds are attributes of the elements of IfcRoot subtypes:
+//
+//	<IfcWall id="i1656" GlobalId="2DWKyvjkf7PffFYiFUDNsy" Name="Basic Wall:Generic - 200mm">
+//
+// The Scanner reads the file as a stream with encoding/xml and returns every element with a GlobalId attribute.
+// Rewrite replaces GlobalId values in place, and copies everything else unchanged, including attribute order,
+// namespace prefixes, white space and comments. Check and Repair find and fix duplicate and invalid GlobalIds,
+// like the spf package does for IFC-SPF files.
+//
+// Usage:
+//
+//	s := ifcxml.NewScanner(file)
+//	for s.Scan() {
+//		e := s.Element()
+//		fmt.Println(e.Id, e.Type, e.GlobalId, e.Line)
+//	}
+//	if err := s.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+package ifcxml
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/woweh/ifcguid"
+)
+
+// Element is an ifcXML element with a GlobalId attribute.
+type Element struct {
+	// Type is the local name of the element, e.g. "IfcWall".
+	Type string
+	// Id is the value of the id attribute, e.g. "i1656".
+	Id string
+	// GlobalId is the value of the GlobalId attribute.
+	GlobalId string
+	// Name is the value of the Name attribute, if any.
+	Name string
+	// Offset is the byte offset of the start tag of the element.
+	Offset int64
+	// Line is the 1-based line number of the start tag.
+	Line int
+	// GlobalIdOffset is the byte offset of the GlobalId value as written in the file, including its quotes.
+	GlobalIdOffset int64
+	// GlobalIdLength is the length in bytes of the GlobalId value as written in the file, including its quotes.
+	GlobalIdLength int
+}
+
+// Scanner reads the elements with a GlobalId attribute of an ifcXML file one by one.
+type Scanner struct {
+	d   *xml.Decoder
+	rec *recorder
+	// onToken, if set, is called after every token with the raw bytes up to the end of the token,
+	// and the element if the token is the start tag of one. It is used by Rewrite.
+	onToken func(raw []byte, e *Element) error
+
+	element Element
+	err     error
+	done    bool
+}
+
+// NewScanner returns a Scanner that reads from r.
+func NewScanner(r io.Reader) *Scanner {
+	rec := &recorder{r: bufio.NewReaderSize(r, 64*1024)}
+	return &Scanner{d: xml.NewDecoder(rec), rec: rec}
+}
+
+// Scan advances the Scanner to the next element with a GlobalId attribute, which is then available through Element.
+// It returns false at the end of the input or after an error, see Err.
+func (s *Scanner) Scan() bool {
+	for !s.done && s.err == nil {
+		start := s.d.InputOffset()
+		line, _ := s.d.InputPos()
+		tok, err := s.d.Token()
+		if err == io.EOF {
+			s.done = true
+			s.err = s.flush(s.rec.buf.Len(), nil)
+			return false
+		}
+		if err != nil {
+			s.err = err
+			return false
+		}
+		// The raw bytes of the token are at the end of the recorded bytes, up to the input offset;
+		// the decoder may have read one byte more.
+		end := int(s.d.InputOffset() - s.rec.start)
+		se, ok := tok.(xml.StartElement)
+		if !ok {
+			s.err = s.flush(end, nil)
+			continue
+		}
+		e, found, err := parseElement(se, start, line, s.rec.buf.Bytes()[int(start-s.rec.start):end])
+		if err != nil {
+			s.err = err
+			return false
+		}
+		if !found {
+			s.err = s.flush(end, nil)
+			continue
+		}
+		if s.err = s.flush(end, &e); s.err != nil {
+			return false
+		}
+		s.element = e
+		return true
+	}
+	return false
+}
+
+// Element returns the element found by the last call to Scan.
+func (s *Scanner) Element() Element {
+	return s.element
+}
+
+// Err returns the first error encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// parseElement returns the element of a start tag, if it has a GlobalId attribute.
+func parseElement(se xml.StartElement, offset int64, line int, raw []byte) (Element, bool, error) {
+	e := Element{Type: se.Name.Local, Offset: offset, Line: line}
+	found := false
+	for _, a := range se.Attr {
+		if a.Name.Space != "" {
+			continue
+		}
+		switch a.Name.Local {
+		case "GlobalId":
+			e.GlobalId = a.Value
+			found = true
+		case "id":
+			e.Id = a.Value
+		case "Name":
+			e.Name = a.Value
+		}
+	}
+	if !found {
+		return e, false, nil
+	}
+	from, to, ok := attributeValue(raw, "GlobalId")
+	if !ok {
+		return e, false, fmt.Errorf("line %d: GlobalId attribute of %s not found", line, e.Type)
+	}
+	e.GlobalIdOffset = offset + int64(from)
+	e.GlobalIdLength = to - from
+	return e, true, nil
+}
+
+// attributeValue returns the start and end of the value of the attribute with the given name in a raw start tag,
+// including its quotes. The tag has been checked by the decoder already, so it is well-formed.
+// Attribute values are skipped as a whole, so text that looks like an attribute inside another value isn't found.
+func attributeValue(raw []byte, name string) (int, int, bool) {
+	isSpace := func(c byte) bool { return c == ' ' || c == '\t' || c == '\n' || c == '\r' }
+	i := 1 // after '<'
+	for i < len(raw) && !isSpace(raw[i]) && raw[i] != '/' && raw[i] != '>' {
+		i++
+	}
+	for i < len(raw) {
+		for i < len(raw) && isSpace(raw[i]) {
+			i++
+		}
+		if i == len(raw) || raw[i] == '/' || raw[i] == '>' {
+			return 0, 0, false
+		}
+		start := i
+		for i < len(raw) && raw[i] != '=' && !isSpace(raw[i]) {
+			i++
+		}
+		attr := string(raw[start:i])
+		for i < len(raw) && (isSpace(raw[i]) || raw[i] == '=') {
+			i++
+		}
+		if i == len(raw) || (raw[i] != '"' && raw[i] != '\'') {
+			return 0, 0, false
+		}
+		end := bytes.IndexByte(raw[i+1:], raw[i])
+		if end < 0 {
+			return 0, 0, false
+		}
+		from, to := i, i+1+end+1
+		if attr == name {
+			return from, to, true
+		}
+		i = to
+	}
+	return 0, 0, false
+}
+
+// flush passes the recorded bytes up to end to onToken, and discards them.
+func (s *Scanner) flush(end int, e *Element) error {
+	var err error
+	if s.onToken != nil {
+		err = s.onToken(s.rec.buf.Bytes()[:end], e)
+	}
+	s.rec.discard(end)
+	return err
+}
+
+// recorder is an io.ByteReader that keeps the bytes it has read, until they are discarded.
+type recorder struct {
+	r *bufio.Reader
+	// buf holds the bytes read since the last discard.
+	buf bytes.Buffer
+	// start is the offset of the first byte in buf.
+	start int64
+}
+
+func (r *recorder) ReadByte() (byte, error) {
+	c, err := r.r.ReadByte()
+	if err == nil {
+		r.buf.WriteByte(c)
+	}
+	return c, err
+}
+
+func (r *recorder) Read(p []byte) (int, error) {
+	// The decoder only uses ReadByte, but io.Reader is required by xml.NewDecoder.
+	if len(p) == 0 {
+		return 0, nil
+	}
+	c, err := r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	p[0] = c
+	return 1, nil
+}
+
+// discard discards the first n recorded bytes.
+func (r *recorder) discard(n int) {
+	r.buf.Next(n)
+	r.start += int64(n)
+	if r.buf.Len() == 0 {
+		r.buf.Reset()
+	}
+}
+
+// Replacer returns the new GlobalId of an element, or false to keep its GlobalId, see Rewrite.
+type Replacer func(e Element) (newId string, ok bool, err error)
+
+// Rewrite copies the ifcXML file read from r to w, and replaces GlobalIds as decided by replace.
+//
+// The Replacer is called for every element with a GlobalId attribute, in file order.
+// New GlobalIds must be valid IFC GUIDs. All other bytes of the file are copied unchanged.
+// Rewrite returns the number of replaced GlobalIds.
+func Rewrite(r io.Reader, w io.Writer, replace Replacer) (int, error) {
+	s := NewScanner(r)
+	count := 0
+	s.onToken = func(raw []byte, e *Element) error {
+		if e == nil {
+			_, err := w.Write(raw)
+			return err
+		}
+		newId, ok, err := replace(*e)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			_, err := w.Write(raw)
+			return err
+		}
+		if err := ifcguid.IsValid(newId); err != nil {
+			return fmt.Errorf("line %d: invalid new GlobalId: %w", e.Line, err)
+		}
+		from := int(e.GlobalIdOffset - s.rec.start)
+		to := from + e.GlobalIdLength
+		quote := raw[from]
+		if _, err := w.Write(raw[:from]); err != nil {
+			return err
+		}
+		if _, err := w.Write([]byte(string(quote) + newId + string(quote))); err != nil {
+			return err
+		}
+		count++
+		_, err = w.Write(raw[to:])
+		return err
+	}
+	for s.Scan() {
+	}
+	return count, s.Err()
+}
+
+// Remap returns a Replacer that replaces every GlobalId with convert(GlobalId),
+// e.g. with the Fork method of an ifcguid.Forker.
+// GlobalIds that convert returns unchanged aren't counted as replaced.
+func Remap(convert func(ifcGuid string) (string, error)) Replacer {
+	return func(e Element) (string, bool, error) {
+		newId, err := convert(e.GlobalId)
+		if err != nil {
+			return "", false, fmt.Errorf("line %d: %w", e.Line, err)
+		}
+		return newId, newId != e.GlobalId, nil
+	}
+}
@@ -0,0 +1,131 @@
+package ifcxml
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _testFile = `<?xml version="1.0" encoding="UTF-8"?>
+<!-- exported for testing, GlobalId="3vB2YO$MX4xv5uCqZZG05x" -->
+<ifc:ifcXML xmlns:ifc="http://www.buildingsmart-tech.org/ifcXML/IFC4/final"
+    xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xmlns="http://www.buildingsmart-tech.org/ifcXML/IFC4/final">
+  <IfcProject id="i1" GlobalId="0YvctVUKr0kugbFTf53O9L" Name="Project &quot;1&quot;">
+    <Declares>
+      <IfcRelDeclares id="i2" GlobalId='1qBj3hqTzDGfbFnzk1U$Xp'/>
+    </Declares>
+  </IfcProject>
+  <IfcWall
+      id="i42"
+      Name="Wall"   GlobalId = "2DWKyvjkf7PffFYiFUDNsy" >
+    <ObjectType>Basic Wall</ObjectType>
+  </IfcWall>
+  <IfcCartesianPoint id="i50" Coordinates="0 0 0"/>
+</ifc:ifcXML>
+`
+
+func Test_Scanner(t *testing.T) {
+	s := NewScanner(strings.NewReader(_testFile))
+	var got []Element
+	for s.Scan() {
+		got = append(got, s.Element())
+	}
+	assert.NoError(t, s.Err())
+
+	if assert.Len(t, got, 3) {
+		assert.Equal(t, "IfcProject", got[0].Type)
+		assert.Equal(t, "i1", got[0].Id)
+		assert.Equal(t, `Project "1"`, got[0].Name)
+		assert.Equal(t, 5, got[0].Line)
+		assert.Equal(t, "1qBj3hqTzDGfbFnzk1U$Xp", got[1].GlobalId)
+		assert.Equal(t, "'1qBj3hqTzDGfbFnzk1U$Xp'", _testFile[got[1].GlobalIdOffset:got[1].GlobalIdOffset+int64(got[1].GlobalIdLength)])
+
+		wall := got[2]
+		assert.Equal(t, "IfcWall", wall.Type)
+		assert.Equal(t, "i42", wall.Id)
+		assert.Equal(t, 10, wall.Line)
+		assert.True(t, strings.HasPrefix(_testFile[wall.Offset:], "<IfcWall\n"))
+		assert.Equal(t, `"2DWKyvjkf7PffFYiFUDNsy"`, _testFile[wall.GlobalIdOffset:wall.GlobalIdOffset+int64(wall.GlobalIdLength)])
+	}
+
+	s = NewScanner(strings.NewReader("<a><b GlobalId='x'></a>"))
+	for s.Scan() {
+	}
+	assert.Error(t, s.Err())
+}
+
+func Test_Rewrite(t *testing.T) {
+	var out bytes.Buffer
+	count, err := Rewrite(strings.NewReader(_testFile), &out, func(e Element) (string, bool, error) {
+		switch e.Id {
+		case "i2":
+			return "0mXQZaOVr7Tf$n6oIcHifF", true, nil
+		case "i42":
+			return "3vB2YO$MX4xv5uCqZZG05x", true, nil
+		}
+		return "", false, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	want := strings.Replace(_testFile, "GlobalId='1qBj3hqTzDGfbFnzk1U$Xp'", "GlobalId='0mXQZaOVr7Tf$n6oIcHifF'", 1)
+	want = strings.Replace(want, `GlobalId = "2DWKyvjkf7PffFYiFUDNsy"`, `GlobalId = "3vB2YO$MX4xv5uCqZZG05x"`, 1)
+	assert.Equal(t, want, out.String())
+
+	out.Reset()
+	count, err = Rewrite(strings.NewReader(_testFile), &out, Remap(func(ifcGuid string) (string, error) { return ifcGuid, nil }))
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Equal(t, _testFile, out.String())
+
+	_, err = Rewrite(strings.NewReader(_testFile), &bytes.Buffer{}, func(Element) (string, bool, error) {
+		return "invalid", true, nil
+	})
+	assert.Error(t, err)
+	_, err = Rewrite(strings.NewReader(_testFile), &bytes.Buffer{}, Remap(func(string) (string, error) {
+		return "", errors.New("failure")
+	}))
+	assert.ErrorContains(t, err, "line 5: failure")
+}
+
+func Test_Scanner_large_input(t *testing.T) {
+	var b strings.Builder
+	b.WriteString("<ifcXML>\n")
+	for i := 0; i < 50_000; i++ {
+		b.WriteString(`  <IfcWall id="i1" GlobalId="2DWKyvjkf7PffFYiFUDNsy"/>` + "\n")
+	}
+	b.WriteString("</ifcXML>\n")
+	var out bytes.Buffer
+	count, err := Rewrite(strings.NewReader(b.String()), &out, Remap(func(string) (string, error) {
+		return "3vB2YO$MX4xv5uCqZZG05x", nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 50_000, count)
+	assert.Equal(t, strings.ReplaceAll(b.String(), "2DWKyvjkf7PffFYiFUDNsy", "3vB2YO$MX4xv5uCqZZG05x"), out.String())
+}
+
+func Test_attributeValue(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want string
+	}{
+		{`<IfcWall GlobalId="a">`, `"a"`},
+		{`<IfcWall id='i1' GlobalId='a'/>`, `'a'`},
+		{"<ifc:IfcWall\n\tid=\"i1\"\n\tGlobalId\n\t=\n\t\"a\"\n>", `"a"`},
+		{`<IfcWall Description=' GlobalId="b"' GlobalId="a">`, `"a"`},
+		{`<IfcWall Description="x GlobalId='b'" GlobalId='a'>`, `'a'`},
+		{`<IfcWall ext:GlobalId="b" GlobalId="a&#36;&quot;">`, `"a&#36;&quot;"`},
+		{`<IfcWall ext:GlobalId="b">`, ``},
+		{`<IfcWall GlobalIds="b"/>`, ``},
+		{`<GlobalId id="b"/>`, ``},
+	}
+	for _, tt := range tests {
+		from, to, ok := attributeValue([]byte(tt.raw), "GlobalId")
+		assert.Equal(t, tt.want != "", ok, tt.raw)
+		if ok {
+			assert.Equal(t, tt.want, tt.raw[from:to], tt.raw)
+		}
+	}
+}
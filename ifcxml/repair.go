@@ -0,0 +1,149 @@
+package ifcxml
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/ifczip"
+	"github.com/woweh/ifcguid/internal/atomicfile"
+	"github.com/woweh/ifcguid/internal/guidcheck"
+)
+
+// Reasons for replacing a GlobalId, see Replacement.
+const (
+	ReasonInvalid   = guidcheck.ReasonInvalid
+	ReasonDuplicate = guidcheck.ReasonDuplicate
+)
+
+// Replacement records a replaced GlobalId.
+type Replacement struct {
+	// Element is the element whose GlobalId was replaced. Its GlobalId field holds the old GlobalId.
+	Element
+	// New is the new GlobalId.
+	New string
+	// Reason explains why the GlobalId was replaced, e.g. ReasonInvalid or ReasonDuplicate.
+	Reason string
+}
+
+// Strategy returns a new GlobalId for an element whose GlobalId must be replaced, see Repairer.
+type Strategy func(e Element) (string, error)
+
+// Random is a Strategy that creates a new random GlobalId with ifcguid.New.
+func Random(Element) (string, error) {
+	return ifcguid.New()
+}
+
+// Derive returns a Strategy that derives the new GlobalId from the old GlobalId and the position of the element,
+// as a name-based UUID (version 5) in the given namespace.
+// Repairing the same file twice gives the same GlobalIds.
+func Derive(namespace uuid.UUID) Strategy {
+	return func(e Element) (string, error) {
+		return guidcheck.Derive(namespace, e.GlobalId+"@"+strconv.FormatInt(e.Offset, 10))
+	}
+}
+
+// Repairer replaces invalid and duplicate GlobalIds in ifcXML files.
+type Repairer struct {
+	// Checker finds the GlobalIds to replace. Duplicates suppressed by its rules are kept.
+	Checker
+	// Strategy creates the new GlobalIds. If nil, Random is used.
+	Strategy Strategy
+}
+
+// Repair copies the ifcXML file read from r to w, replacing invalid and duplicate GlobalIds,
+// using DefaultRules and Random GlobalIds.
+func Repair(r io.ReadSeeker, w io.Writer) ([]Replacement, error) {
+	rp := Repairer{Checker: Checker{Rules: DefaultRules}}
+	return rp.Repair(r, w)
+}
+
+// RepairFile repairs the ifcXML file src with Repair, and writes the result to dst.
+// If src is an .ifczip archive, dst is an .ifczip archive too, see ifczip.RewriteFile.
+// If mapping isn't empty, the replacements are written to a CSV file with that name, see WriteMapping.
+func RepairFile(src, dst, mapping string) ([]Replacement, error) {
+	rp := Repairer{Checker: Checker{Rules: DefaultRules}}
+	return rp.RepairFile(src, dst, mapping)
+}
+
+// Repair copies the ifcXML file read from r to w, replacing every invalid GlobalId,
+// and every occurrence but the first of each duplicate GlobalId. All other bytes are copied unchanged.
+//
+// The file is read twice: once to find the problems with the Checker, and once to rewrite it.
+func (rp *Repairer) Repair(r io.ReadSeeker, w io.Writer) ([]Replacement, error) {
+	report, err := rp.Check(r)
+	if err != nil {
+		return nil, err
+	}
+	// Elements are identified by the offset of their GlobalId, because element ids may be duplicated too.
+	duplicates := make([][]int64, len(report.Duplicates))
+	for i, d := range report.Duplicates {
+		for _, e := range d.Elements {
+			duplicates[i] = append(duplicates[i], e.GlobalIdOffset)
+		}
+	}
+	invalid := make([]int64, len(report.Invalid))
+	for i, inv := range report.Invalid {
+		invalid[i] = inv.GlobalIdOffset
+	}
+	reasons := guidcheck.NewReasons(duplicates, invalid)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	strategy := rp.Strategy
+	if strategy == nil {
+		strategy = Random
+	}
+	var replacements []Replacement
+	_, err = Rewrite(r, w, func(e Element) (string, bool, error) {
+		reason, ok := reasons[e.GlobalIdOffset]
+		if !ok {
+			return "", false, nil
+		}
+		newId, err := strategy(e)
+		if err != nil {
+			return "", false, err
+		}
+		replacements = append(replacements, Replacement{Element: e, New: newId, Reason: reason})
+		return newId, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replacements, nil
+}
+
+// RepairFile repairs the ifcXML file src, and writes the result to dst.
+// If src is an .ifczip archive, dst is an .ifczip archive too, see ifczip.RewriteFile.
+// If mapping isn't empty, the replacements are written to a CSV file with that name, see WriteMapping.
+func (rp *Repairer) RepairFile(src, dst, mapping string) ([]Replacement, error) {
+	var replacements []Replacement
+	err := ifczip.RewriteFile(src, dst, ifczip.FormatXml, func(r io.ReadSeeker, w io.Writer) error {
+		var err error
+		replacements, err = rp.Repair(r, w)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if mapping != "" {
+		err := atomicfile.Write(mapping, func(w io.Writer) error {
+			return WriteMapping(w, replacements)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return replacements, nil
+}
+
+// WriteMapping writes replacements as CSV, with the element id and type, and the old and new GlobalIds.
+func WriteMapping(w io.Writer, replacements []Replacement) error {
+	mw := guidcheck.NewMappingWriter(w)
+	for _, r := range replacements {
+		_ = mw.Write(r.Id, r.Type, r.GlobalId, r.New, r.Reason)
+	}
+	return mw.Flush()
+}
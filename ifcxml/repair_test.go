@@ -0,0 +1,103 @@
+package ifcxml
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Repair(t *testing.T) {
+	var out bytes.Buffer
+	replacements, err := Repair(strings.NewReader(_duplicatesFile), &out)
+	assert.NoError(t, err)
+	if !assert.Len(t, replacements, 2) {
+		return
+	}
+	assert.Equal(t, "i11", replacements[0].Id)
+	assert.Equal(t, ReasonDuplicate, replacements[0].Reason)
+	assert.Equal(t, "i13", replacements[1].Id)
+	assert.Equal(t, ReasonInvalid, replacements[1].Reason)
+
+	// Only the GlobalId values are replaced, keeping their quotes; the GlobalId in the description is kept.
+	want := strings.NewReplacer(
+		`'2DWKyvjkf7PffFYiFUDNs&#121;'`, `'`+replacements[0].New+`'`,
+		`GlobalId="4DWKyvjkf7PffFYiFUDNsy"`, `GlobalId="`+replacements[1].New+`"`,
+	).Replace(_duplicatesFile)
+	assert.Equal(t, want, out.String())
+	report, err := Check(strings.NewReader(out.String()))
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+
+	rp := Repairer{Strategy: Derive(uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8"))}
+	var first, second bytes.Buffer
+	_, err = rp.Repair(strings.NewReader(_duplicatesFile), &first)
+	assert.NoError(t, err)
+	_, err = rp.Repair(strings.NewReader(_duplicatesFile), &second)
+	assert.NoError(t, err)
+	assert.Equal(t, first.String(), second.String())
+}
+
+func Test_RepairFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "model.ifcxml")
+	dst := filepath.Join(dir, "repaired.ifcxml")
+	mapping := filepath.Join(dir, "mapping.csv")
+	assert.NoError(t, os.WriteFile(src, []byte(_duplicatesFile), 0o644))
+
+	replacements, err := RepairFile(src, dst, mapping)
+	assert.NoError(t, err)
+	assert.Len(t, replacements, 2)
+	data, err := os.ReadFile(mapping)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(data), "Id,Type,Old,New,Reason\ni11,IfcWall,2DWKyvjkf7PffFYiFUDNsy,"))
+
+	report, err := CheckFile(dst)
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+
+	// Without rules, the material associations are repaired too, here in place.
+	rp := Repairer{}
+	replacements, err = rp.RepairFile(src, src, "")
+	assert.NoError(t, err)
+	if assert.Len(t, replacements, 3) {
+		assert.Equal(t, "i21", replacements[2].Id)
+	}
+	data, err = os.ReadFile(src)
+	assert.NoError(t, err)
+	report, err = rp.Check(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+	assert.Empty(t, report.Suppressed)
+}
+
+func Test_RepairFile_ifczip(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "model.ifczip")
+	dst := filepath.Join(dir, "repaired.ifczip")
+	f, err := os.Create(src)
+	assert.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("model.ifcXML")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+
+	report, err := CheckFile(src)
+	assert.NoError(t, err)
+	assert.True(t, report.Broken())
+
+	replacements, err := RepairFile(src, dst, "")
+	assert.NoError(t, err)
+	assert.Len(t, replacements, 2)
+	report, err = CheckFile(dst)
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+}
@@ -0,0 +1,264 @@
+// Package ifczip reads and writes .ifczip archives, i.e. zip archives with a single IFC-SPF or ifcXML file.
+//
+// The file-level operations of the spf and ifcxml packages, like spf.CheckFile and spf.RepairFile,
+// use OpenFile and RewriteFile, so they accept .ifczip archives as well as plain files.
+// The IFC file is read directly from the archive, without unpacking it to a temporary file.
+// Archives are recognized by their content, not by their name, so .zip files work too.
+//
+// Usage:
+//
+//	a, err := ifczip.Open("model.ifczip")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer a.Close()
+//	fmt.Println(a.Member.Name, a.Format)
+package ifczip
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/woweh/ifcguid/internal/atomicfile"
+)
+
+// _zipSignature is the signature of the first local file header of a zip archive.
+var _zipSignature = []byte("PK\x03\x04")
+
+// Format is the format of an IFC file.
+type Format int
+
+// Supported formats.
+const (
+	// FormatSpf is IFC-SPF (ISO 10303-21), with the extension .ifc.
+	FormatSpf Format = iota + 1
+	// FormatXml is ifcXML (ISO 10303-28), with the extension .ifcxml.
+	FormatXml
+)
+
+// String returns the name of the format, e.g. "IFC-SPF".
+func (f Format) String() string {
+	switch f {
+	case FormatSpf:
+		return "IFC-SPF"
+	case FormatXml:
+		return "ifcXML"
+	}
+	return "Format(" + strconv.Itoa(int(f)) + ")"
+}
+
+// FormatOf returns the format of an IFC file by the extension of its name, or 0 if it isn't an IFC file.
+func FormatOf(name string) Format {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".ifc":
+		return FormatSpf
+	case ".ifcxml":
+		return FormatXml
+	}
+	return 0
+}
+
+// IsZip reports whether the named file is a zip archive.
+func IsZip(name string) (bool, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	head := make([]byte, len(_zipSignature))
+	if _, err := io.ReadFull(f, head); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return false, nil
+		}
+		return false, err
+	}
+	return bytes.Equal(head, _zipSignature), nil
+}
+
+// Archive is an .ifczip archive opened for reading.
+type Archive struct {
+	// Member is the IFC file in the archive.
+	Member *zip.File
+	// Format is the format of the IFC file.
+	Format Format
+
+	rc *zip.ReadCloser
+}
+
+// Open opens the named .ifczip archive, and finds the IFC file in it.
+// The archive must hold exactly one file with the extension .ifc or .ifcxml, in any folder;
+// other files, e.g. images referenced by the model, are allowed.
+func Open(name string) (*Archive, error) {
+	rc, err := zip.OpenReader(name)
+	if err != nil {
+		return nil, err
+	}
+	a := &Archive{rc: rc}
+	for _, f := range rc.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(path.Base(f.Name), ".") {
+			continue
+		}
+		format := FormatOf(f.Name)
+		if format == 0 {
+			continue
+		}
+		if a.Member != nil {
+			rc.Close()
+			return nil, fmt.Errorf("%s: more than one IFC file: %s and %s", name, a.Member.Name, f.Name)
+		}
+		a.Member = f
+		a.Format = format
+	}
+	if a.Member == nil {
+		rc.Close()
+		return nil, fmt.Errorf("%s: no .ifc or .ifcxml file in the archive", name)
+	}
+	return a, nil
+}
+
+// Close closes the archive.
+func (a *Archive) Close() error {
+	return a.rc.Close()
+}
+
+// Open returns a reader for the IFC file in the archive.
+// The reader can only seek to the start of the file, which decompresses the file again,
+// e.g. for the two passes of spf.Repair.
+func (a *Archive) Open() io.ReadSeekCloser {
+	return &memberReader{f: a.Member}
+}
+
+// Rewrite writes a copy of the archive to w, with the IFC file replaced by the output of write.
+// The IFC file keeps its name, compression method and modification time.
+// All other files are copied without decompressing them.
+func (a *Archive) Rewrite(w io.Writer, write func(w io.Writer) error) error {
+	zw := zip.NewWriter(w)
+	if err := zw.SetComment(a.rc.Comment); err != nil {
+		return err
+	}
+	for _, f := range a.rc.File {
+		if f != a.Member {
+			if err := zw.Copy(f); err != nil {
+				return err
+			}
+			continue
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.Name,
+			Comment:  f.Comment,
+			Method:   f.Method,
+			Modified: f.Modified,
+		})
+		if err != nil {
+			return err
+		}
+		if err := write(fw); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// OpenFile opens the named IFC file of the given format for reading:
+// a plain file, or the IFC file in an .ifczip archive.
+// The reader can seek to the start of the file, see Archive.Open.
+func OpenFile(name string, format Format) (io.ReadSeekCloser, error) {
+	isZip, err := IsZip(name)
+	if err != nil {
+		return nil, err
+	}
+	if !isZip {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+	a, err := Open(name)
+	if err != nil {
+		return nil, err
+	}
+	if a.Format != format {
+		a.Close()
+		return nil, fmt.Errorf("%s: %s is an %s file, not %s", name, a.Member.Name, a.Format, format)
+	}
+	return &archiveReader{ReadSeekCloser: a.Open(), archive: a}, nil
+}
+
+// Rewrite reads the IFC file src of the given format, see OpenFile, and writes it to w with rewrite.
+// If src is an .ifczip archive, an .ifczip archive with the same files is written, see Archive.Rewrite.
+func Rewrite(src string, w io.Writer, format Format, rewrite func(r io.ReadSeeker, w io.Writer) error) error {
+	in, err := OpenFile(src, format)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	if ar, ok := in.(*archiveReader); ok {
+		return ar.archive.Rewrite(w, func(w io.Writer) error {
+			return rewrite(in, w)
+		})
+	}
+	return rewrite(in, w)
+}
+
+// RewriteFile is like Rewrite, but writes to the file dst.
+// dst is written through a temporary file, so it may be src, and it is left unchanged if rewrite fails.
+func RewriteFile(src, dst string, format Format, rewrite func(r io.ReadSeeker, w io.Writer) error) error {
+	return atomicfile.Write(dst, func(w io.Writer) error {
+		return Rewrite(src, w, format, rewrite)
+	})
+}
+
+// memberReader reads a file in an archive, and decompresses it again when it seeks to the start.
+type memberReader struct {
+	f  *zip.File
+	rc io.ReadCloser
+}
+
+func (r *memberReader) Read(p []byte) (int, error) {
+	if r.rc == nil {
+		rc, err := r.f.Open()
+		if err != nil {
+			return 0, err
+		}
+		r.rc = rc
+	}
+	return r.rc.Read(p)
+}
+
+func (r *memberReader) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, fmt.Errorf("%s: can only seek to the start of a compressed file", r.f.Name)
+	}
+	err := r.Close()
+	return 0, err
+}
+
+func (r *memberReader) Close() error {
+	if r.rc == nil {
+		return nil
+	}
+	err := r.rc.Close()
+	r.rc = nil
+	return err
+}
+
+// archiveReader is the reader returned by OpenFile for archives; it closes the archive too.
+type archiveReader struct {
+	io.ReadSeekCloser
+	archive *Archive
+}
+
+func (r *archiveReader) Close() error {
+	err := r.ReadSeekCloser.Close()
+	if closeErr := r.archive.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package ifczip
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _model = "ISO-10303-21;\nDATA;\n#42= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',#5,'Wall',$,$,$,$,$,$);\nENDSEC;\nEND-ISO-10303-21;\n"
+
+// writeZip writes an archive with the given files, in order, and returns its name.
+func writeZip(t *testing.T, files ...string) string {
+	name := filepath.Join(t.TempDir(), "model.ifczip")
+	f, err := os.Create(name)
+	assert.NoError(t, err)
+	zw := zip.NewWriter(f)
+	assert.NoError(t, zw.SetComment("exported for testing"))
+	for i := 0; i < len(files); i += 2 {
+		w, err := zw.Create(files[i])
+		assert.NoError(t, err)
+		_, err = w.Write([]byte(files[i+1]))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+	return name
+}
+
+func Test_FormatOf(t *testing.T) {
+	tests := []struct {
+		name string
+		want Format
+	}{
+		{"model.ifc", FormatSpf},
+		{"folder/Model.IFC", FormatSpf},
+		{"model.ifcXML", FormatXml},
+		{"model.ifczip", 0},
+		{"readme.txt", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatOf(tt.name))
+		})
+	}
+	assert.Equal(t, "IFC-SPF", FormatSpf.String())
+	assert.Equal(t, "ifcXML", FormatXml.String())
+	assert.Equal(t, "Format(0)", Format(0).String())
+}
+
+func Test_Open(t *testing.T) {
+	name := writeZip(t, "textures/", "", "textures/brick.png", "png", "__MACOSX/._model.ifc", "junk", "model.ifc", _model)
+	a, err := Open(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "model.ifc", a.Member.Name)
+	assert.Equal(t, FormatSpf, a.Format)
+
+	r := a.Open()
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, _model, string(data))
+	_, err = r.Seek(0, io.SeekStart)
+	assert.NoError(t, err)
+	data, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Equal(t, _model, string(data))
+	_, err = r.Seek(10, io.SeekStart)
+	assert.Error(t, err)
+	assert.NoError(t, r.Close())
+	assert.NoError(t, a.Close())
+
+	_, err = Open(writeZip(t, "readme.txt", "no model"))
+	assert.ErrorContains(t, err, "no .ifc or .ifcxml file")
+	_, err = Open(writeZip(t, "a.ifc", _model, "b.ifcxml", "<ifcXML/>"))
+	assert.ErrorContains(t, err, "more than one IFC file")
+}
+
+func Test_OpenFile(t *testing.T) {
+	plain := filepath.Join(t.TempDir(), "model.ifc")
+	assert.NoError(t, os.WriteFile(plain, []byte(_model), 0o644))
+	for _, name := range []string{plain, writeZip(t, "model/model.ifc", _model)} {
+		isZip, err := IsZip(name)
+		assert.NoError(t, err)
+		assert.Equal(t, name != plain, isZip)
+
+		r, err := OpenFile(name, FormatSpf)
+		assert.NoError(t, err)
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, _model, string(data))
+		assert.NoError(t, r.Close())
+	}
+
+	_, err := OpenFile(writeZip(t, "model.ifcxml", "<ifcXML/>"), FormatSpf)
+	assert.ErrorContains(t, err, "model.ifcxml is an ifcXML file, not IFC-SPF")
+	_, err = OpenFile(filepath.Join(t.TempDir(), "missing.ifc"), FormatSpf)
+	assert.Error(t, err)
+}
+
+func Test_RewriteFile(t *testing.T) {
+	upper := func(r io.ReadSeeker, w io.Writer) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bytes.ToUpper(data))
+		return err
+	}
+	src := writeZip(t, "docs/readme.txt", "hello", "Project/Model.ifc", _model, "z.txt", "last")
+	dst := filepath.Join(t.TempDir(), "out.ifczip")
+	assert.NoError(t, RewriteFile(src, dst, FormatSpf, upper))
+
+	zr, err := zip.OpenReader(dst)
+	assert.NoError(t, err)
+	defer zr.Close()
+	assert.Equal(t, "exported for testing", zr.Comment)
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	assert.Equal(t, []string{"docs/readme.txt", "Project/Model.ifc", "z.txt"}, names)
+	a, err := Open(dst)
+	assert.NoError(t, err)
+	defer a.Close()
+	data, err := io.ReadAll(a.Open())
+	assert.NoError(t, err)
+	assert.Equal(t, strings.ToUpper(_model), string(data))
+
+	plain := filepath.Join(t.TempDir(), "model.ifc")
+	assert.NoError(t, os.WriteFile(plain, []byte(_model), 0o644))
+	var out bytes.Buffer
+	assert.NoError(t, Rewrite(plain, &out, FormatSpf, upper))
+	assert.Equal(t, strings.ToUpper(_model), out.String())
+}
+
+func Test_RewriteFile_in_place(t *testing.T) {
+	upper := func(r io.ReadSeeker, w io.Writer) error {
+		data, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(bytes.ToUpper(data))
+		return err
+	}
+	src := writeZip(t, "Model.ifc", _model)
+	assert.NoError(t, RewriteFile(src, src, FormatSpf, upper))
+	a, err := Open(src)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(a.Open())
+	assert.NoError(t, err)
+	assert.NoError(t, a.Close())
+	assert.Equal(t, strings.ToUpper(_model), string(data))
+
+	plain := filepath.Join(t.TempDir(), "model.ifc")
+	assert.NoError(t, os.WriteFile(plain, []byte(_model), 0o644))
+	assert.NoError(t, RewriteFile(plain, plain, FormatSpf, upper))
+	data, err = os.ReadFile(plain)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.ToUpper(_model), string(data))
+
+	// A failed rewrite leaves no partial output.
+	failing := func(r io.ReadSeeker, w io.Writer) error {
+		_, _ = io.WriteString(w, "ISO-10303-21;")
+		return io.ErrUnexpectedEOF
+	}
+	assert.ErrorIs(t, RewriteFile(plain, plain, FormatSpf, failing), io.ErrUnexpectedEOF)
+	data, err = os.ReadFile(plain)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.ToUpper(_model), string(data))
+	dst := filepath.Join(t.TempDir(), "out.ifc")
+	assert.Error(t, RewriteFile(plain, dst, FormatSpf, failing))
+	assert.NoFileExists(t, dst)
+}
@@ -0,0 +1,115 @@
+package ifcguid
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// _iModelLocalIdBits is the number of bits of the local id in an iModel element id.
+	_iModelLocalIdBits = 40
+	// _iModelLocalIdMask masks the local id in an iModel element id.
+	_iModelLocalIdMask = 1<<_iModelLocalIdBits - 1
+)
+
+// IModelElementId is a decoded Bentley iModel element id, see ToIModelElementId.
+type IModelElementId struct {
+	// BriefcaseId is the 24-bit id of the briefcase that created the element.
+	BriefcaseId uint32
+	// LocalId is the 40-bit id of the element within the briefcase.
+	LocalId uint64
+	// Fingerprint identifies the iModel the element belongs to, see IModelFingerprint.
+	Fingerprint uint64
+}
+
+// String returns the element id as an iTwin Id64String, e.g. "0x20000000001".
+func (id IModelElementId) String() string {
+	return "0x" + strconv.FormatUint(id.Value(), 16)
+}
+
+// Value returns the 64-bit element id.
+func (id IModelElementId) Value() uint64 {
+	return uint64(id.BriefcaseId)<<_iModelLocalIdBits | id.LocalId
+}
+
+// BelongsTo reports whether the element id was packed with the given iModel id.
+func (id IModelElementId) BelongsTo(iModelId string) bool {
+	fingerprint, err := IModelFingerprint(iModelId)
+	return err == nil && fingerprint == id.Fingerprint
+}
+
+// IModelFingerprint returns the 64-bit fingerprint of an iModel id (a GUID string).
+// It consists of the first 8 bytes of the SHA-256 hash of the iModel id.
+func IModelFingerprint(iModelId string) (uint64, error) {
+	u, err := uuid.Parse(iModelId)
+	if err != nil {
+		return 0, fmt.Errorf("invalid iModel id: %w", err)
+	}
+	hash := sha256.Sum256(u[:])
+	return binary.BigEndian.Uint64(hash[:8]), nil
+}
+
+// FromIModelElementId converts a Bentley iModel element id to an IFC GUID.
+//
+// The iModelId is the GUID of the iModel, and elementId is an iTwin Id64String (e.g. "0x20000000001").
+// Element ids are only unique within an iModel, so the IFC GUID is built from the iModel fingerprint
+// (upper 64 bits, see IModelFingerprint) and the element id (lower 64 bits).
+// The same element id in different iModels thus yields different IFC GUIDs, unlike FromInt64.
+// Use ToIModelElementId to decode it.
+func FromIModelElementId(iModelId string, elementId string) (string, error) {
+	value, err := parseId64String(elementId)
+	if err != nil {
+		return "", err
+	}
+	fingerprint, err := IModelFingerprint(iModelId)
+	if err != nil {
+		return "", err
+	}
+	bytes := make([]byte, 16)
+	binary.BigEndian.PutUint64(bytes[:8], fingerprint)
+	binary.BigEndian.PutUint64(bytes[8:], value)
+	u, err := uuid.FromBytes(bytes)
+	if err != nil {
+		return "", err
+	}
+	return FromUuid(u)
+}
+
+// ToIModelElementId decodes an IFC GUID created by FromIModelElementId.
+// It returns the briefcase id, the local id and the fingerprint of the iModel.
+// The iModel id itself can't be recovered; use IModelElementId.BelongsTo to check it.
+func ToIModelElementId(ifcGuid string) (IModelElementId, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return IModelElementId{}, err
+	}
+	value := binary.BigEndian.Uint64(u[8:])
+	if value == 0 {
+		return IModelElementId{}, fmt.Errorf("the IFC GUID doesn't hold an iModel element id: %v", ifcGuid)
+	}
+	return IModelElementId{
+		BriefcaseId: uint32(value >> _iModelLocalIdBits),
+		LocalId:     value & _iModelLocalIdMask,
+		Fingerprint: binary.BigEndian.Uint64(u[:8]),
+	}, nil
+}
+
+// parseId64String parses an iTwin Id64String, i.e. a hexadecimal number with a "0x" prefix.
+func parseId64String(elementId string) (uint64, error) {
+	if !strings.HasPrefix(elementId, "0x") && !strings.HasPrefix(elementId, "0X") {
+		return 0, fmt.Errorf("invalid iModel element id (missing 0x prefix): %v", elementId)
+	}
+	value, err := strconv.ParseUint(elementId[2:], 16, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid iModel element id: %v", elementId)
+	}
+	if value == 0 {
+		return 0, fmt.Errorf("invalid iModel element id (zero): %v", elementId)
+	}
+	return value, nil
+}
@@ -0,0 +1,98 @@
+package ifcguid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_IModelElementId_conversions(t *testing.T) {
+	const iModelId = "b4e2d6a1-3c5f-4e7a-9b8c-1d2e3f405162"
+
+	tests := []struct {
+		name            string
+		iModelId        string
+		elementId       string
+		wantBriefcaseId uint32
+		wantLocalId     uint64
+		wantError       bool
+	}{
+		{
+			name:            "Element from briefcase 2",
+			iModelId:        iModelId,
+			elementId:       "0x20000000001",
+			wantBriefcaseId: 2,
+			wantLocalId:     1,
+		},
+		{
+			name:            "Element from the master briefcase",
+			iModelId:        iModelId,
+			elementId:       "0x1d",
+			wantBriefcaseId: 0,
+			wantLocalId:     0x1d,
+		},
+		{
+			name:            "Largest element id",
+			iModelId:        iModelId,
+			elementId:       "0xffffffffffffffff",
+			wantBriefcaseId: 0xffffff,
+			wantLocalId:     0xffffffffff,
+		},
+		{
+			name:      "Missing prefix",
+			iModelId:  iModelId,
+			elementId: "20000000001",
+			wantError: true,
+		},
+		{
+			name:      "Zero element id",
+			iModelId:  iModelId,
+			elementId: "0x0",
+			wantError: true,
+		},
+		{
+			name:      "Element id too large",
+			iModelId:  iModelId,
+			elementId: "0x1ffffffffffffffff",
+			wantError: true,
+		},
+		{
+			name:      "Invalid iModel id",
+			iModelId:  "not-a-guid",
+			elementId: "0x1d",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIfcGuid, err := FromIModelElementId(tt.iModelId, tt.elementId)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+
+			got, err := ToIModelElementId(gotIfcGuid)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantBriefcaseId, got.BriefcaseId)
+			assert.Equal(t, tt.wantLocalId, got.LocalId)
+			assert.Equal(t, tt.elementId, got.String())
+			assert.True(t, got.BelongsTo(tt.iModelId))
+			assert.False(t, got.BelongsTo("0a1b2c3d-4e5f-4a6b-8c7d-8e9f0a1b2c3d"))
+		})
+	}
+}
+
+func Test_FromIModelElementId_does_not_collide_across_iModels(t *testing.T) {
+	a, err := FromIModelElementId("b4e2d6a1-3c5f-4e7a-9b8c-1d2e3f405162", "0x20000000001")
+	assert.NoError(t, err)
+	b, err := FromIModelElementId("0a1b2c3d-4e5f-4a6b-8c7d-8e9f0a1b2c3d", "0x20000000001")
+	assert.NoError(t, err)
+	assert.NotEqual(t, a, b)
+
+	// The lower 64 bits still hold the element id.
+	value, err := ToInt64(a)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0x20000000001), value)
+}
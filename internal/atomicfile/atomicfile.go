@@ -0,0 +1,46 @@
+// Package atomicfile writes files through a temporary file, so that a file is either written completely or not at all.
+package atomicfile
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// _newFileMode is the permission of files that didn't exist before.
+const _newFileMode = 0o644
+
+// Write calls write with a temporary file in the directory of name, and renames it to name if write succeeds.
+// Otherwise, the temporary file is removed and an existing file with that name is left unchanged.
+//
+// Because name is only replaced at the end, write may read the file that it replaces,
+// e.g. to rewrite a file in place. An existing file keeps its permissions, and symbolic links are followed.
+func Write(name string, write func(w io.Writer) error) (err error) {
+	mode := os.FileMode(_newFileMode)
+	if resolved, err := filepath.EvalSymlinks(name); err == nil {
+		name = resolved
+	}
+	if info, err := os.Stat(name); err == nil {
+		mode = info.Mode().Perm()
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+	}()
+	err = write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(f.Name(), mode); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
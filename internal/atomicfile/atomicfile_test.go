@@ -0,0 +1,71 @@
+package atomicfile
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Write(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "model.ifc")
+
+	assert.NoError(t, Write(name, func(w io.Writer) error {
+		_, err := io.WriteString(w, "first")
+		return err
+	}))
+	data, err := os.ReadFile(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", string(data))
+	info, err := os.Stat(name)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(_newFileMode), info.Mode().Perm())
+
+	// The file can be read while it is replaced, and keeps its permissions.
+	assert.NoError(t, os.Chmod(name, 0o600))
+	assert.NoError(t, Write(name, func(w io.Writer) error {
+		old, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(old, " second"...))
+		return err
+	}))
+	data, err = os.ReadFile(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "first second", string(data))
+	info, err = os.Stat(name)
+	assert.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// A failed write leaves the file unchanged, and no temporary file behind.
+	err = Write(name, func(w io.Writer) error {
+		_, _ = io.WriteString(w, "partial")
+		return errors.New("failed")
+	})
+	assert.EqualError(t, err, "failed")
+	data, err = os.ReadFile(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "first second", string(data))
+	entries, err := os.ReadDir(dir)
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	// Symbolic links are followed, instead of being replaced.
+	link := filepath.Join(dir, "link.ifc")
+	if err := os.Symlink(name, link); err == nil {
+		assert.NoError(t, Write(link, func(w io.Writer) error {
+			_, err := io.WriteString(w, "third")
+			return err
+		}))
+		data, err = os.ReadFile(name)
+		assert.NoError(t, err)
+		assert.Equal(t, "third", string(data))
+	}
+
+	assert.Error(t, Write(filepath.Join(dir, "missing", "model.ifc"), func(io.Writer) error { return nil }))
+}
@@ -0,0 +1,166 @@
+// Package guidcheck implements the format-independent parts of checking and repairing the GlobalIds of a file,
+// shared by the spf and ifcxml packages.
+//
+// Elements are identified by the byte offset of their GlobalId in the file,
+// because both their ids and their GlobalIds may be duplicated.
+package guidcheck
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/woweh/ifcguid"
+)
+
+// Reasons for replacing a GlobalId.
+const (
+	ReasonInvalid   = "invalid"
+	ReasonDuplicate = "duplicate"
+)
+
+// Index groups the elements of a file by GlobalId. Elements are numbered in the order they are added.
+type Index struct {
+	byGlobalId map[string][]int
+	order      []string
+	n          int
+}
+
+// Group is a GlobalId and the numbers of the elements that use it, in file order.
+type Group struct {
+	GlobalId string
+	Elements []int
+}
+
+// Add adds an element with the given GlobalId, and returns its number.
+func (x *Index) Add(globalId string) int {
+	if x.byGlobalId == nil {
+		x.byGlobalId = map[string][]int{}
+	}
+	if _, ok := x.byGlobalId[globalId]; !ok {
+		x.order = append(x.order, globalId)
+	}
+	x.byGlobalId[globalId] = append(x.byGlobalId[globalId], x.n)
+	x.n++
+	return x.n - 1
+}
+
+// Duplicates returns the GlobalIds used by more than one element, ordered by first use.
+// GlobalIds are compared case-sensitively.
+func (x *Index) Duplicates() []Group {
+	var groups []Group
+	for _, id := range x.order {
+		if elements := x.byGlobalId[id]; len(elements) > 1 {
+			groups = append(groups, Group{GlobalId: id, Elements: elements})
+		}
+	}
+	return groups
+}
+
+// AllTypes reports whether every type in types is one of ruleTypes, ignoring case.
+// It is false if ruleTypes is empty.
+func AllTypes(ruleTypes, types []string) bool {
+	if len(ruleTypes) == 0 {
+		return false
+	}
+	for _, t := range types {
+		if !containsFold(ruleTypes, t) {
+			return false
+		}
+	}
+	return true
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// Reasons holds the reason for replacing the GlobalIds of a file, by the offset of the GlobalId.
+type Reasons map[int64]string
+
+// NewReasons returns the reasons for replacing every invalid GlobalId,
+// and every occurrence but the first of each duplicate GlobalId.
+// duplicates holds the offsets of the occurrences of each duplicate GlobalId, in file order.
+func NewReasons(duplicates [][]int64, invalid []int64) Reasons {
+	reasons := Reasons{}
+	for _, offsets := range duplicates {
+		for _, offset := range offsets[1:] {
+			reasons[offset] = ReasonDuplicate
+		}
+	}
+	for _, offset := range invalid {
+		reasons[offset] = ReasonInvalid
+	}
+	return reasons
+}
+
+// Derive returns the GlobalId of the name-based UUID (version 5) of name in the given namespace.
+func Derive(namespace uuid.UUID, name string) (string, error) {
+	return ifcguid.FromUuid(uuid.NewSHA1(namespace, []byte(name)))
+}
+
+// MappingWriter writes replaced GlobalIds as CSV, with the element id and type, and the old and new GlobalIds.
+type MappingWriter struct {
+	cw     *csv.Writer
+	header bool
+}
+
+// NewMappingWriter returns a MappingWriter that writes to w.
+func NewMappingWriter(w io.Writer) *MappingWriter {
+	return &MappingWriter{cw: csv.NewWriter(w)}
+}
+
+// Write writes a replaced GlobalId, preceded by the header line if it is the first one.
+func (mw *MappingWriter) Write(id, typ, oldId, newId, reason string) error {
+	if err := mw.writeHeader(); err != nil {
+		return err
+	}
+	return mw.cw.Write([]string{id, typ, oldId, newId, reason})
+}
+
+// Flush writes the header line if nothing was written yet, and flushes the CSV writer.
+func (mw *MappingWriter) Flush() error {
+	_ = mw.writeHeader()
+	mw.cw.Flush()
+	return mw.cw.Error()
+}
+
+// writeHeader writes the header line, unless it was written already.
+func (mw *MappingWriter) writeHeader() error {
+	if mw.header {
+		return nil
+	}
+	mw.header = true
+	return mw.cw.Write([]string{"Id", "Type", "Old", "New", "Reason"})
+}
+
+// LogWriter writes the problems found by a check as CSV, one line per element.
+type LogWriter struct {
+	cw *csv.Writer
+}
+
+// NewLogWriter returns a LogWriter that writes to w, and writes the header line.
+func NewLogWriter(w io.Writer) *LogWriter {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"Problem", "GlobalId", "Id", "Type", "Name", "Line", "Rule", "Error"})
+	return &LogWriter{cw: cw}
+}
+
+// Write writes a problem with an element, e.g. "duplicate". Errors are reported by Flush.
+func (lw *LogWriter) Write(problem, globalId, id, typ, name string, line int, rule, errText string) {
+	_ = lw.cw.Write([]string{problem, globalId, id, typ, name, strconv.Itoa(line), rule, errText})
+}
+
+// Flush flushes the CSV writer, and returns the first error.
+func (lw *LogWriter) Flush() error {
+	lw.cw.Flush()
+	return lw.cw.Error()
+}
@@ -0,0 +1,65 @@
+package guidcheck
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Index(t *testing.T) {
+	var x Index
+	assert.Empty(t, x.Duplicates())
+	for _, id := range []string{"b", "a", "B", "a", "c", "b", "a"} {
+		x.Add(id)
+	}
+	assert.Equal(t, []Group{
+		{GlobalId: "b", Elements: []int{0, 5}},
+		{GlobalId: "a", Elements: []int{1, 3, 6}},
+	}, x.Duplicates())
+}
+
+func Test_AllTypes(t *testing.T) {
+	assert.True(t, AllTypes([]string{"IFCRELASSOCIATESMATERIAL"}, []string{"IfcRelAssociatesMaterial", "IFCRELASSOCIATESMATERIAL"}))
+	assert.False(t, AllTypes([]string{"IFCRELASSOCIATESMATERIAL"}, []string{"IfcRelAssociatesMaterial", "IfcWall"}))
+	assert.False(t, AllTypes(nil, []string{"IfcWall"}))
+}
+
+func Test_NewReasons(t *testing.T) {
+	reasons := NewReasons([][]int64{{10, 20, 30}, {15, 25}}, []int64{20, 40})
+	assert.Equal(t, Reasons{20: ReasonInvalid, 30: ReasonDuplicate, 25: ReasonDuplicate, 40: ReasonInvalid}, reasons)
+}
+
+func Test_Derive(t *testing.T) {
+	namespace := uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	first, err := Derive(namespace, "2DWKyvjkf7PffFYiFUDNsy#42")
+	assert.NoError(t, err)
+	second, err := Derive(namespace, "2DWKyvjkf7PffFYiFUDNsy#42")
+	assert.NoError(t, err)
+	assert.Equal(t, first, second)
+	other, err := Derive(namespace, "2DWKyvjkf7PffFYiFUDNsy#43")
+	assert.NoError(t, err)
+	assert.NotEqual(t, first, other)
+}
+
+func Test_MappingWriter(t *testing.T) {
+	var buf bytes.Buffer
+	mw := NewMappingWriter(&buf)
+	assert.NoError(t, mw.Flush())
+	assert.Equal(t, "Id,Type,Old,New,Reason\n", buf.String())
+
+	buf.Reset()
+	mw = NewMappingWriter(&buf)
+	assert.NoError(t, mw.Write("#42", "IFCWALL", "2DWKyvjkf7PffFYiFUDNsy", "3vB2YO$MX4xv5uCqZZG05x", ReasonDuplicate))
+	assert.NoError(t, mw.Flush())
+	assert.Equal(t, "Id,Type,Old,New,Reason\n#42,IFCWALL,2DWKyvjkf7PffFYiFUDNsy,3vB2YO$MX4xv5uCqZZG05x,duplicate\n", buf.String())
+}
+
+func Test_LogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	lw := NewLogWriter(&buf)
+	lw.Write("invalid", "x", "i1", "IfcWall", "Wall, 1", 3, "", "too short")
+	assert.NoError(t, lw.Flush())
+	assert.Equal(t, "Problem,GlobalId,Id,Type,Name,Line,Rule,Error\ninvalid,x,i1,IfcWall,\"Wall, 1\",3,,too short\n", buf.String())
+}
@@ -0,0 +1,314 @@
+package ifcguid
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Names of the built-in identifier schemes.
+const (
+	SchemeNameIfcGuid = "ifc"
+	SchemeNameUuid    = "uuid"
+	SchemeNameRevit   = "revit"
+	SchemeNameAutoCad = "autocad"
+	SchemeNameInt32   = "int32"
+	SchemeNameInt64   = "int64"
+	SchemeNameBase64  = "base64"
+	SchemeNameString  = "string"
+)
+
+// IdScheme describes an external identifier format that can be mapped to and from a UUID,
+// and thus to and from an IFC GUID.
+//
+// Implementations must be safe for concurrent use.
+type IdScheme interface {
+	// Name returns the unique name of the scheme, e.g. "revit".
+	Name() string
+	// Detect reports whether value looks like an identifier of this scheme.
+	Detect(value string) bool
+	// ToUuid converts an identifier of this scheme to a UUID.
+	ToUuid(value string) (uuid.UUID, error)
+	// FromUuid converts a UUID back to an identifier of this scheme.
+	// Schemes that are not reversible return an error.
+	FromUuid(u uuid.UUID) (string, error)
+	// Reversible reports whether FromUuid can recover identifiers produced by ToUuid.
+	Reversible() bool
+}
+
+// Built-in identifier schemes.
+// They are registered by default and can be looked up by name with LookupScheme.
+var (
+	// SchemeIfcGuid handles 22-character IFC GUIDs.
+	SchemeIfcGuid IdScheme = ifcGuidScheme{}
+	// SchemeUuid handles UUID strings in any form accepted by FromUuidString.
+	SchemeUuid IdScheme = uuidScheme{}
+	// SchemeRevit handles Revit UniqueIds. It is not reversible.
+	SchemeRevit IdScheme = revitScheme{}
+	// SchemeAutoCad handles hexadecimal AutoCAD handles.
+	SchemeAutoCad IdScheme = autoCadScheme{}
+	// SchemeInt32 handles decimal 32-bit integers.
+	SchemeInt32 IdScheme = int32Scheme{}
+	// SchemeInt64 handles decimal 64-bit integers.
+	SchemeInt64 IdScheme = int64Scheme{}
+	// SchemeBase64 handles short strings of IFC base-64 digits, read as an IFC GUID without its leading zeros,
+	// e.g. "1A" for "000000000000000000001A".
+	SchemeBase64 IdScheme = base64Scheme{}
+	// SchemeString handles arbitrary strings, see FromString. It is not reversible.
+	SchemeString IdScheme = stringScheme{}
+)
+
+// _registry holds all registered identifier schemes, keyed by name.
+var _registry = struct {
+	sync.RWMutex
+	schemes map[string]IdScheme
+}{
+	schemes: map[string]IdScheme{},
+}
+
+func init() {
+	for _, s := range []IdScheme{
+		SchemeIfcGuid, SchemeUuid, SchemeRevit, SchemeAutoCad, SchemeInt32, SchemeInt64, SchemeBase64, SchemeString,
+	} {
+		mustRegisterScheme(s)
+	}
+}
+
+// mustRegisterScheme registers a built-in identifier scheme, and panics if that fails.
+func mustRegisterScheme(s IdScheme) {
+	if err := RegisterScheme(s); err != nil {
+		panic(err)
+	}
+}
+
+// RegisterScheme adds an identifier scheme to the registry.
+// It returns an error if the scheme is nil, has an empty name, or a scheme with the same name is already registered.
+func RegisterScheme(s IdScheme) error {
+	if s == nil {
+		return fmt.Errorf("the scheme must not be nil")
+	}
+	name := s.Name()
+	if name == "" {
+		return fmt.Errorf("the scheme name must not be empty")
+	}
+	_registry.Lock()
+	defer _registry.Unlock()
+	if _, ok := _registry.schemes[name]; ok {
+		return fmt.Errorf("a scheme named %q is already registered", name)
+	}
+	_registry.schemes[name] = s
+	return nil
+}
+
+// UnregisterScheme removes the identifier scheme with the given name from the registry.
+// It reports whether a scheme was removed.
+func UnregisterScheme(name string) bool {
+	_registry.Lock()
+	defer _registry.Unlock()
+	if _, ok := _registry.schemes[name]; !ok {
+		return false
+	}
+	delete(_registry.schemes, name)
+	return true
+}
+
+// LookupScheme returns the registered identifier scheme with the given name.
+func LookupScheme(name string) (IdScheme, bool) {
+	_registry.RLock()
+	defer _registry.RUnlock()
+	s, ok := _registry.schemes[name]
+	return s, ok
+}
+
+// Schemes returns all registered identifier schemes, sorted by name.
+func Schemes() []IdScheme {
+	_registry.RLock()
+	defer _registry.RUnlock()
+	result := make([]IdScheme, 0, len(_registry.schemes))
+	for _, s := range _registry.schemes {
+		result = append(result, s)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name() < result[j].Name() })
+	return result
+}
+
+// Convert converts value from one identifier scheme to another, using a UUID as the intermediate representation.
+//
+// For example, Convert(SchemeRevit, SchemeIfcGuid, uniqueId) is equivalent to FromRevitUniqueId(uniqueId).
+func Convert(from, to IdScheme, value string) (string, error) {
+	if from == nil || to == nil {
+		return "", fmt.Errorf("the source and target schemes must not be nil")
+	}
+	u, err := from.ToUuid(value)
+	if err != nil {
+		return "", err
+	}
+	return to.FromUuid(u)
+}
+
+// ConvertByName is like Convert, but looks up the schemes by name.
+func ConvertByName(from, to string, value string) (string, error) {
+	fromScheme, ok := LookupScheme(from)
+	if !ok {
+		return "", fmt.Errorf("unknown scheme: %q", from)
+	}
+	toScheme, ok := LookupScheme(to)
+	if !ok {
+		return "", fmt.Errorf("unknown scheme: %q", to)
+	}
+	return Convert(fromScheme, toScheme, value)
+}
+
+// ifcGuidScheme implements IdScheme for IFC GUIDs.
+type ifcGuidScheme struct{}
+
+func (ifcGuidScheme) Name() string                           { return SchemeNameIfcGuid }
+func (ifcGuidScheme) Detect(value string) bool               { return IsValid(value) == nil }
+func (ifcGuidScheme) ToUuid(value string) (uuid.UUID, error) { return ToUuid(value) }
+func (ifcGuidScheme) FromUuid(u uuid.UUID) (string, error)   { return FromUuid(u) }
+func (ifcGuidScheme) Reversible() bool                       { return true }
+
+// uuidScheme implements IdScheme for UUID strings.
+type uuidScheme struct{}
+
+func (uuidScheme) Name() string { return SchemeNameUuid }
+
+func (uuidScheme) Detect(value string) bool {
+	_, err := parseUuid(value)
+	return err == nil
+}
+
+func (uuidScheme) ToUuid(value string) (uuid.UUID, error) { return parseUuid(value) }
+
+func (uuidScheme) FromUuid(u uuid.UUID) (string, error) { return u.String(), nil }
+
+func (uuidScheme) Reversible() bool { return true }
+
+// revitScheme implements IdScheme for Revit UniqueIds.
+type revitScheme struct{}
+
+func (revitScheme) Name() string                           { return SchemeNameRevit }
+func (revitScheme) Detect(value string) bool               { return IsValidRevitUniqueId(value) }
+func (revitScheme) ToUuid(value string) (uuid.UUID, error) { return revitUniqueIdToUuid(value) }
+func (revitScheme) Reversible() bool                       { return false }
+
+func (revitScheme) FromUuid(uuid.UUID) (string, error) {
+	return "", fmt.Errorf("a Revit uniqueId cannot be recovered from a UUID")
+}
+
+// autoCadScheme implements IdScheme for AutoCAD handles.
+type autoCadScheme struct{}
+
+// _autoCadHandle matches hexadecimal handles that fit into 64 bits.
+var _autoCadHandle = regexp.MustCompile(`^[0-9A-Fa-f]{1,16}$`)
+
+func (autoCadScheme) Name() string { return SchemeNameAutoCad }
+
+func (autoCadScheme) Detect(value string) bool {
+	if !_autoCadHandle.MatchString(value) {
+		return false
+	}
+	_, err := strconv.ParseInt(value, 16, 64)
+	return err == nil
+}
+
+func (autoCadScheme) ToUuid(value string) (uuid.UUID, error) { return autoCadHandleToUuid(value) }
+func (autoCadScheme) FromUuid(u uuid.UUID) (string, error)   { return uuidToAutoCadHandle(u) }
+func (autoCadScheme) Reversible() bool                       { return true }
+
+// int32Scheme implements IdScheme for decimal 32-bit integers.
+type int32Scheme struct{}
+
+func (int32Scheme) Name() string { return SchemeNameInt32 }
+
+func (int32Scheme) Detect(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 32)
+	return err == nil
+}
+
+func (int32Scheme) ToUuid(value string) (uuid.UUID, error) {
+	v, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return int64ToUuid(v)
+}
+
+func (int32Scheme) FromUuid(u uuid.UUID) (string, error) {
+	v, err := uuidToInt64(u)
+	if err != nil {
+		return "", err
+	}
+	if v < math.MinInt32 || v > math.MaxInt32 {
+		return "", fmt.Errorf("the UUID does not hold a 32-bit integer: %v", u)
+	}
+	return strconv.FormatInt(v, 10), nil
+}
+
+func (int32Scheme) Reversible() bool { return true }
+
+// int64Scheme implements IdScheme for decimal 64-bit integers.
+type int64Scheme struct{}
+
+func (int64Scheme) Name() string { return SchemeNameInt64 }
+
+func (int64Scheme) Detect(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 64)
+	return err == nil
+}
+
+func (int64Scheme) ToUuid(value string) (uuid.UUID, error) { return intStringToUuid(value, 10) }
+func (int64Scheme) FromUuid(u uuid.UUID) (string, error)   { return uuidToIntString(u, "%d") }
+func (int64Scheme) Reversible() bool                       { return true }
+
+// base64Scheme implements IdScheme for IFC GUIDs without their leading zeros.
+type base64Scheme struct{}
+
+func (base64Scheme) Name() string     { return SchemeNameBase64 }
+func (base64Scheme) Reversible() bool { return true }
+
+func (base64Scheme) Detect(value string) bool {
+	return len(value) > 0 && len(value) < 22 && strings.Trim(value, _conversionTable) == ""
+}
+
+func (s base64Scheme) ToUuid(value string) (uuid.UUID, error) {
+	if !s.Detect(value) {
+		return uuid.Nil, fmt.Errorf("the given string isn't a short base-64 GlobalId: %v", value)
+	}
+	return ToUuid(strings.Repeat("0", 22-len(value)) + value)
+}
+
+func (base64Scheme) FromUuid(u uuid.UUID) (string, error) {
+	ifcGuid, err := FromUuid(u)
+	if err != nil {
+		return "", err
+	}
+	if short := strings.TrimLeft(ifcGuid, "0"); short != "" {
+		return short, nil
+	}
+	return "0", nil
+}
+
+// stringScheme implements IdScheme for arbitrary strings.
+type stringScheme struct{}
+
+func (stringScheme) Name() string             { return SchemeNameString }
+func (stringScheme) Detect(value string) bool { return len(value) > 0 }
+func (stringScheme) Reversible() bool         { return false }
+
+func (stringScheme) ToUuid(value string) (uuid.UUID, error) {
+	if len(value) == 0 {
+		return uuid.Nil, fmt.Errorf("the input string must not be empty")
+	}
+	return uuid.FromBytes(stringTo16Bytes(value))
+}
+
+func (stringScheme) FromUuid(uuid.UUID) (string, error) {
+	return "", fmt.Errorf("a string cannot be recovered from a UUID")
+}
@@ -0,0 +1,205 @@
+package ifcguid
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// upperScheme is a test scheme that stores UUIDs as upper case hex strings with a prefix.
+type upperScheme struct{}
+
+func (upperScheme) Name() string             { return "test-upper" }
+func (upperScheme) Detect(value string) bool { return strings.HasPrefix(value, "UP-") }
+func (upperScheme) Reversible() bool         { return true }
+
+func (upperScheme) ToUuid(value string) (uuid.UUID, error) {
+	if !strings.HasPrefix(value, "UP-") {
+		return uuid.Nil, fmt.Errorf("missing prefix")
+	}
+	return uuid.Parse(value[3:])
+}
+
+func (upperScheme) FromUuid(u uuid.UUID) (string, error) {
+	return "UP-" + strings.ToUpper(u.String()), nil
+}
+
+func Test_Schemes_builtins(t *testing.T) {
+	var names []string
+	for _, s := range Schemes() {
+		names = append(names, s.Name())
+	}
+	for _, want := range []string{
+		SchemeNameIfcGuid, SchemeNameUuid, SchemeNameRevit, SchemeNameAutoCad,
+		SchemeNameInt32, SchemeNameInt64, SchemeNameBase64, SchemeNameString,
+	} {
+		assert.Contains(t, names, want)
+		s, ok := LookupScheme(want)
+		assert.True(t, ok)
+		assert.Equal(t, want, s.Name())
+	}
+}
+
+func Test_Convert(t *testing.T) {
+	tests := []struct {
+		name    string
+		from    IdScheme
+		to      IdScheme
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "Revit to IFC GUID",
+			from:  SchemeRevit,
+			to:    SchemeIfcGuid,
+			value: "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e",
+			want:  "2DWKyvjkf7PffFYiFUDNsy",
+		},
+		{
+			name:  "AutoCAD to IFC GUID",
+			from:  SchemeAutoCad,
+			to:    SchemeIfcGuid,
+			value: "1A",
+			want:  "000000000000000000000Q",
+		},
+		{
+			name:  "IFC GUID to AutoCAD",
+			from:  SchemeIfcGuid,
+			to:    SchemeAutoCad,
+			value: "000000000000000000000Q",
+			want:  "1a",
+		},
+		{
+			name:  "Int64 to IFC GUID",
+			from:  SchemeInt64,
+			to:    SchemeIfcGuid,
+			value: "123456789",
+			want:  "000000000000000007MyqL",
+		},
+		{
+			name:  "UUID to IFC GUID",
+			from:  SchemeUuid,
+			to:    SchemeIfcGuid,
+			value: "01cf62c8-e9bc-bf88-0000-000000000005",
+			want:  "01psB8wRo$Y00000000005",
+		},
+		{
+			name:  "Base 64 to IFC GUID",
+			from:  SchemeBase64,
+			to:    SchemeIfcGuid,
+			value: "1A",
+			want:  "000000000000000000001A",
+		},
+		{
+			name:  "AutoCAD to base 64",
+			from:  SchemeAutoCad,
+			to:    SchemeBase64,
+			value: "1A",
+			want:  "Q",
+		},
+		{
+			name:    "Base 64 with a character outside the IFC alphabet",
+			from:    SchemeBase64,
+			to:      SchemeIfcGuid,
+			value:   "1-A",
+			wantErr: true,
+		},
+		{
+			name:  "AutoCAD to Int32",
+			from:  SchemeAutoCad,
+			to:    SchemeInt32,
+			value: "FF",
+			want:  "255",
+		},
+		{
+			name:    "Int64 out of range for Int32",
+			from:    SchemeInt64,
+			to:      SchemeInt32,
+			value:   "9223372036854775807",
+			wantErr: true,
+		},
+		{
+			name:    "IFC GUID to Revit is not reversible",
+			from:    SchemeIfcGuid,
+			to:      SchemeRevit,
+			value:   "2DWKyvjkf7PffFYiFUDNsy",
+			wantErr: true,
+		},
+		{
+			name:    "IFC GUID to string is not reversible",
+			from:    SchemeIfcGuid,
+			to:      SchemeString,
+			value:   "2DWKyvjkf7PffFYiFUDNsy",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid source value",
+			from:    SchemeIfcGuid,
+			to:      SchemeUuid,
+			value:   "invalid",
+			wantErr: true,
+		},
+		{
+			name:    "Nil scheme",
+			from:    nil,
+			to:      SchemeUuid,
+			value:   "1A",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.from, tt.to, tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_RegisterScheme(t *testing.T) {
+	assert.NoError(t, RegisterScheme(upperScheme{}))
+	defer UnregisterScheme("test-upper")
+
+	assert.Error(t, RegisterScheme(upperScheme{}), "duplicate names must be rejected")
+	assert.Error(t, RegisterScheme(nil))
+	for _, s := range Schemes() {
+		assert.Error(t, RegisterScheme(s), "built-in scheme %q is registered", s.Name())
+	}
+
+	got, err := ConvertByName("test-upper", SchemeNameIfcGuid, "UP-01CF62C8-E9BC-BF88-0000-000000000005")
+	assert.NoError(t, err)
+	assert.Equal(t, "01psB8wRo$Y00000000005", got)
+
+	got, err = ConvertByName(SchemeNameIfcGuid, "test-upper", "01psB8wRo$Y00000000005")
+	assert.NoError(t, err)
+	assert.Equal(t, "UP-01CF62C8-E9BC-BF88-0000-000000000005", got)
+
+	_, err = ConvertByName("unknown", SchemeNameIfcGuid, "x")
+	assert.Error(t, err)
+
+	assert.True(t, UnregisterScheme("test-upper"))
+	assert.False(t, UnregisterScheme("test-upper"))
+}
+
+func Test_Scheme_Detect(t *testing.T) {
+	assert.True(t, SchemeIfcGuid.Detect("2DWKyvjkf7PffFYiFUDNsy"))
+	assert.False(t, SchemeIfcGuid.Detect("1A"))
+	assert.True(t, SchemeRevit.Detect("8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e"))
+	assert.True(t, SchemeUuid.Detect("{01cf62c8-e9bc-bf88-0000-000000000005}"))
+	assert.True(t, SchemeAutoCad.Detect("1A"))
+	assert.False(t, SchemeAutoCad.Detect("1G"))
+	assert.True(t, SchemeInt32.Detect("-42"))
+	assert.False(t, SchemeInt32.Detect("9223372036854775807"))
+	assert.True(t, SchemeInt64.Detect("9223372036854775807"))
+	assert.True(t, SchemeString.Detect("anything"))
+	assert.False(t, SchemeString.Detect(""))
+}
@@ -0,0 +1,212 @@
+package ifcguid
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// SchemeNameSpeckle is the name of the built-in Speckle object id scheme.
+	SchemeNameSpeckle = "speckle"
+	// SpeckleGlobalIdKey is the property that FillSpeckleGlobalIds writes the IFC GUID to.
+	SpeckleGlobalIdKey = "globalId"
+)
+
+// SchemeSpeckle handles Speckle object ids, see FromSpeckleId.
+var SchemeSpeckle IdScheme = speckleScheme{}
+
+// _speckleId matches a Speckle object id: a 32 character hexadecimal hash.
+var _speckleId = regexp.MustCompile(`^[0-9A-Fa-f]{32}$`)
+
+func init() {
+	mustRegisterScheme(SchemeSpeckle)
+}
+
+// IsValidSpeckleId checks if a string is a Speckle object id.
+func IsValidSpeckleId(speckleId string) bool {
+	return len(speckleId) == 32 && _speckleId.MatchString(speckleId)
+}
+
+// FromSpeckleId converts a Speckle object id to an IFC GUID.
+// A Speckle object id is a 128-bit hash written as 32 hexadecimal characters, so it fits exactly into an IFC GUID.
+func FromSpeckleId(speckleId string) (string, error) {
+	u, err := speckleIdToUuid(speckleId)
+	if err != nil {
+		return "", err
+	}
+	return FromUuid(u)
+}
+
+// ToSpeckleId converts an IFC GUID to a Speckle object id (lower case hexadecimal), which is the same as ToHex.
+func ToSpeckleId(ifcGuid string) (string, error) {
+	return ToHex(ifcGuid)
+}
+
+// FillSpeckleGlobalIds sets the SpeckleGlobalIdKey property on every object in a Speckle object JSON,
+// whose applicationId is a Revit UniqueId, using FromRevitUniqueId.
+// Nested objects and arrays are processed recursively; existing GlobalIds are kept,
+// unless they are empty, null, or an object or array.
+//
+// The JSON is edited in place: new properties are added after the last property of their object,
+// and all other bytes, including key order, white space and escapes, are kept as they are.
+// It returns the updated JSON and the number of GlobalIds that were filled in.
+func FillSpeckleGlobalIds(objectJson []byte) ([]byte, int, error) {
+	edits, err := speckleGlobalIdEdits(objectJson)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error parsing Speckle object: %w", err)
+	}
+	var result bytes.Buffer
+	result.Grow(len(objectJson) + len(edits)*40)
+	pos := int64(0)
+	for _, e := range edits {
+		result.Write(objectJson[pos:e.from])
+		result.WriteString(e.text)
+		pos = e.to
+	}
+	result.Write(objectJson[pos:])
+	return result.Bytes(), len(edits), nil
+}
+
+// speckleEdit replaces the bytes from:to of a Speckle object JSON with text.
+type speckleEdit struct {
+	from, to int64
+	text     string
+}
+
+// speckleObject is an object or array that FillSpeckleGlobalIds is in.
+type speckleObject struct {
+	isArray bool
+	// key is the last key read, and isKey reports whether the next token is a key.
+	key   string
+	isKey bool
+	// applicationId is the value of the applicationId property, if it is a string.
+	applicationId string
+	// hasGlobalId reports whether the object has a SpeckleGlobalIdKey property with a value that is kept.
+	hasGlobalId bool
+	// emptyFrom and emptyTo are the offsets of a SpeckleGlobalIdKey value that is replaced, if any:
+	// an empty string, null, or an object or array.
+	emptyFrom, emptyTo int64
+	// end is the offset after the last value of the object.
+	end int64
+}
+
+// speckleGlobalIdEdits returns the edits that fill in the missing GlobalIds of a Speckle object JSON, in file order.
+func speckleGlobalIdEdits(objectJson []byte) ([]speckleEdit, error) {
+	decoder := json.NewDecoder(bytes.NewReader(objectJson))
+	decoder.UseNumber()
+	var stack []*speckleObject
+	var edits []speckleEdit
+	// value records the end of a value in the enclosing object.
+	value := func(end int64) {
+		if n := len(stack); n > 0 && !stack[n-1].isArray {
+			stack[n-1].end = end
+			stack[n-1].isKey = true
+		}
+	}
+	for {
+		tok, err := decoder.Token()
+		if err == io.EOF && len(stack) > 0 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return nil, err
+		}
+		end := decoder.InputOffset()
+		var top *speckleObject
+		if n := len(stack); n > 0 {
+			top = stack[n-1]
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if top != nil && !top.isArray && top.key == SpeckleGlobalIdKey {
+				top.emptyFrom = end - 1
+			}
+			stack = append(stack, &speckleObject{isArray: tok == json.Delim('['), isKey: true})
+			continue
+		case json.Delim('}'):
+			stack = stack[:len(stack)-1]
+			globalIdEnd(stack, end)
+			if !top.hasGlobalId && IsValidRevitUniqueId(top.applicationId) {
+				ifcGuid, err := FromRevitUniqueId(top.applicationId)
+				if err != nil {
+					return nil, err
+				}
+				if top.emptyTo > 0 {
+					// Drop the edits of objects nested in a replaced value.
+					kept := edits[:0]
+					for _, e := range edits {
+						if e.from < top.emptyFrom || e.to > top.emptyTo {
+							kept = append(kept, e)
+						}
+					}
+					edits = append(kept, speckleEdit{from: top.emptyFrom, to: top.emptyTo, text: `"` + ifcGuid + `"`})
+				} else {
+					property := `,"` + SpeckleGlobalIdKey + `":"` + ifcGuid + `"`
+					edits = append(edits, speckleEdit{from: top.end, to: top.end, text: property})
+				}
+			}
+		case json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			globalIdEnd(stack, end)
+		default:
+			if top != nil && !top.isArray && top.isKey {
+				top.key, _ = tok.(string)
+				top.isKey = false
+				continue
+			}
+			if top != nil && !top.isArray {
+				switch top.key {
+				case "applicationId":
+					top.applicationId, _ = tok.(string)
+				case SpeckleGlobalIdKey:
+					switch tok {
+					case "":
+						top.emptyFrom, top.emptyTo = end-int64(len(`""`)), end
+					case nil:
+						top.emptyFrom, top.emptyTo = end-int64(len("null")), end
+					default:
+						top.hasGlobalId = true
+					}
+				}
+			}
+		}
+		if len(stack) == 0 {
+			break
+		}
+		value(end)
+	}
+	// Objects end after the objects nested in them, so the edits must be sorted.
+	sort.Slice(edits, func(i, j int) bool { return edits[i].from < edits[j].from })
+	return edits, nil
+}
+
+// globalIdEnd records the end of an object or array, if it is the SpeckleGlobalIdKey value of the enclosing object.
+func globalIdEnd(stack []*speckleObject, end int64) {
+	if n := len(stack); n > 0 && !stack[n-1].isArray && !stack[n-1].isKey && stack[n-1].key == SpeckleGlobalIdKey {
+		stack[n-1].emptyTo = end
+	}
+}
+
+// speckleIdToUuid converts a Speckle object id to a UUID.
+func speckleIdToUuid(speckleId string) (uuid.UUID, error) {
+	if !IsValidSpeckleId(speckleId) {
+		return uuid.Nil, fmt.Errorf("the given string isn't a Speckle object id: %v", speckleId)
+	}
+	return hexToUuid(speckleId)
+}
+
+// speckleScheme implements IdScheme for Speckle object ids.
+type speckleScheme struct{}
+
+func (speckleScheme) Name() string                           { return SchemeNameSpeckle }
+func (speckleScheme) Detect(value string) bool               { return IsValidSpeckleId(value) }
+func (speckleScheme) ToUuid(value string) (uuid.UUID, error) { return speckleIdToUuid(value) }
+func (speckleScheme) FromUuid(u uuid.UUID) (string, error)   { return uuidToHex(u), nil }
+func (speckleScheme) Reversible() bool                       { return true }
+func (speckleScheme) Confidence(string) float64              { return 0.7 }
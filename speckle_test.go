@@ -0,0 +1,127 @@
+package ifcguid
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_SpeckleId_conversions(t *testing.T) {
+	tests := []struct {
+		name      string
+		speckleId string
+		wantError bool
+	}{
+		{
+			name:      "Object id",
+			speckleId: "4b1a5c9f8e0d2c3b7a6f5e4d3c2b1a09",
+		},
+		{
+			name:      "Upper case object id",
+			speckleId: "4B1A5C9F8E0D2C3B7A6F5E4D3C2B1A09",
+		},
+		{
+			name:      "Too short",
+			speckleId: "4b1a5c9f8e0d2c3b7a6f5e4d3c2b1a0",
+			wantError: true,
+		},
+		{
+			name:      "Dashed GUID",
+			speckleId: "4b1a5c9f-8e0d-2c3b-7a6f-5e4d3c2b1a09",
+			wantError: true,
+		},
+		{
+			name:      "All zeros",
+			speckleId: "00000000000000000000000000000000",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIfcGuid, err := FromSpeckleId(tt.speckleId)
+			if tt.wantError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NoError(t, IsValid(gotIfcGuid))
+
+			gotSpeckleId, err := ToSpeckleId(gotIfcGuid)
+			assert.NoError(t, err)
+			assert.Equal(t, "4b1a5c9f8e0d2c3b7a6f5e4d3c2b1a09", gotSpeckleId)
+		})
+	}
+}
+
+func Test_FillSpeckleGlobalIds(t *testing.T) {
+	objectJson := `{
+		"id": "4b1a5c9f8e0d2c3b7a6f5e4d3c2b1a09",
+		"speckle_type": "Objects.BuiltElements.Wall:Objects.BuiltElements.Revit.RevitWall",
+		"applicationId": "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e",
+		"height": 3000.5,
+		"elements": [
+			{"applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd"},
+			{"applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e75c6", "globalId": "keepThisGlobalIdValue0"},
+			{"applicationId": "not-a-revit-id"}
+		]
+	}`
+
+	got, count, err := FillSpeckleGlobalIds([]byte(objectJson))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	var obj struct {
+		GlobalId string      `json:"globalId"`
+		Height   json.Number `json:"height"`
+		Elements []struct {
+			GlobalId string `json:"globalId"`
+		} `json:"elements"`
+	}
+	assert.NoError(t, json.Unmarshal(got, &obj))
+	assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", obj.GlobalId)
+	assert.Equal(t, "3000.5", obj.Height.String())
+	assert.Equal(t, "00lQsbQXP4OA7Ejivjtxsz", obj.Elements[0].GlobalId)
+	assert.Equal(t, "keepThisGlobalIdValue0", obj.Elements[1].GlobalId)
+	assert.Empty(t, obj.Elements[2].GlobalId)
+
+	_, _, err = FillSpeckleGlobalIds([]byte("{not json"))
+	assert.Error(t, err)
+	_, _, err = FillSpeckleGlobalIds([]byte(`{"applicationId": "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e"`))
+	assert.Error(t, err)
+}
+
+func Test_FillSpeckleGlobalIds_in_place(t *testing.T) {
+	objectJson := `{"zName": "<Wall> & \u00e9", "applicationId": "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e",
+  "elements": [{"globalId": "", "applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd"},
+    {"globalId": null, "applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd"}],
+  "big": 12345678901234567890123}`
+
+	got, count, err := FillSpeckleGlobalIds([]byte(objectJson))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Equal(t, `{"zName": "<Wall> & \u00e9", "applicationId": "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e",
+  "elements": [{"globalId": "00lQsbQXP4OA7Ejivjtxsz", "applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd"},
+    {"globalId": "00lQsbQXP4OA7Ejivjtxsz", "applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd"}],
+  "big": 12345678901234567890123,"globalId":"2DWKyvjkf7PffFYiFUDNsy"}`, string(got))
+
+	// Filling in again changes nothing.
+	again, count, err := FillSpeckleGlobalIds(got)
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Equal(t, string(got), string(again))
+}
+
+func Test_FillSpeckleGlobalIds_non_scalar(t *testing.T) {
+	objectJson := `[{"globalId": {"value": "x", "nested": {"applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd"}},
+  "applicationId": "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e"},
+ {"applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd", "globalId": [1, 2]}]`
+
+	got, count, err := FillSpeckleGlobalIds([]byte(objectJson))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, `[{"globalId": "2DWKyvjkf7PffFYiFUDNsy",
+  "applicationId": "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e"},
+ {"applicationId": "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd", "globalId": "00lQsbQXP4OA7Ejivjtxsz"}]`, string(got))
+}
@@ -0,0 +1,197 @@
+package spf
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/ifczip"
+	"github.com/woweh/ifcguid/internal/guidcheck"
+)
+
+// Duplicate is a GlobalId used by more than one entity of a file.
+type Duplicate struct {
+	// GlobalId is the shared GlobalId.
+	GlobalId string
+	// Entities lists the entities that use the GlobalId, in file order.
+	Entities []Entity
+	// Rule is the name of the rule that suppressed the duplicate, if any.
+	Rule string
+}
+
+// Invalid is an entity with a GlobalId that isn't a valid IFC GUID.
+type Invalid struct {
+	Entity
+	// Err is the error returned by ifcguid.IsValid.
+	Err error
+}
+
+// Rule suppresses known-benign duplicates, see Checker.
+type Rule struct {
+	// Name identifies the rule in reports.
+	Name string
+	// Types suppresses duplicates whose entities all have one of these types, e.g. "IFCRELASSOCIATESMATERIAL".
+	// Types are compared case-insensitively.
+	Types []string
+	// Match, if set, suppresses duplicates it returns true for.
+	// A duplicate is suppressed if it matches Types or Match.
+	Match func(d Duplicate) bool
+}
+
+// DefaultRules are the rules used by Check.
+var DefaultRules = []Rule{
+	{
+		// Revit exports one IfcRelAssociatesMaterial per material and element category,
+		// and some versions reuse the same GlobalId for all of them.
+		Name:  "revit-material-associations",
+		Types: []string{"IFCRELASSOCIATESMATERIAL"},
+	},
+}
+
+// Report is the result of checking the GlobalIds of an IFC-SPF file.
+type Report struct {
+	// Entities is the number of entities with a GlobalId.
+	Entities int
+	// Duplicates lists the GlobalIds used by more than one entity, ordered by first use.
+	Duplicates []Duplicate
+	// Suppressed lists the duplicates suppressed by a rule, ordered by first use.
+	Suppressed []Duplicate
+	// Invalid lists the entities with an invalid GlobalId, in file order.
+	Invalid []Invalid
+	// Schema is the name of the schema of the file, e.g. "IFC4", or empty if it isn't known, see LookupSchema.
+	// Missing and Unexpected are only reported for known schemas.
+	Schema string
+	// Missing lists the instances of IfcRoot subtypes without a GlobalId, i.e. whose first attribute
+	// isn't a string, in file order.
+	Missing []Entity
+	// Unexpected lists the instances of other entity types whose first attribute is a valid GlobalId, in file order.
+	// They don't make the file broken, since any string attribute may look like a GlobalId,
+	// but they often come from exporters that write the wrong entity type.
+	Unexpected []Entity
+}
+
+// Broken reports whether the file has duplicate, invalid or missing GlobalIds, ignoring suppressed duplicates.
+func (r *Report) Broken() bool {
+	return len(r.Duplicates) > 0 || len(r.Invalid) > 0 || len(r.Missing) > 0
+}
+
+// WriteLog writes the duplicate, suppressed, invalid, missing and unexpected GlobalIds as CSV, one line per entity.
+func (r *Report) WriteLog(w io.Writer) error {
+	lw := guidcheck.NewLogWriter(w)
+	write := func(problem string, e Entity, rule, errText string) {
+		lw.Write(problem, e.GlobalId, "#"+strconv.FormatUint(e.Id, 10), e.Type, e.Name, e.Line, rule, errText)
+	}
+	for _, d := range r.Duplicates {
+		for _, e := range d.Entities {
+			write("duplicate", e, "", "")
+		}
+	}
+	for _, d := range r.Suppressed {
+		for _, e := range d.Entities {
+			write("suppressed", e, d.Rule, "")
+		}
+	}
+	for _, inv := range r.Invalid {
+		write("invalid", inv.Entity, "", inv.Err.Error())
+	}
+	for _, e := range r.Missing {
+		write("missing", e, "", "")
+	}
+	for _, e := range r.Unexpected {
+		write("unexpected", e, "", "")
+	}
+	return lw.Flush()
+}
+
+// Checker finds duplicate and invalid GlobalIds in IFC-SPF files.
+type Checker struct {
+	// Rules suppress known-benign duplicates.
+	Rules []Rule
+	// Filter selects the entities with a GlobalId. If nil, IsRoot is used.
+	Filter Filter
+}
+
+// Check checks the GlobalIds of the IFC-SPF file read from r, using DefaultRules.
+func Check(r io.Reader) (*Report, error) {
+	c := Checker{Rules: DefaultRules}
+	return c.Check(r)
+}
+
+// CheckFile checks the GlobalIds of the IFC-SPF file with the given name, using DefaultRules.
+// The file may be an .ifczip archive, see ifczip.OpenFile.
+func CheckFile(name string) (*Report, error) {
+	f, err := ifczip.OpenFile(name, ifczip.FormatSpf)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Check(f)
+}
+
+// Check checks the GlobalIds of the IFC-SPF file read from r.
+// Every GlobalId is validated with ifcguid.IsValid, and compared case-sensitively to the others.
+// If the schema of the file is known, instances of IfcRoot subtypes without a GlobalId,
+// and instances of other types with a GlobalId, are reported too.
+func (c *Checker) Check(r io.Reader) (*Report, error) {
+	s := NewScanner(r)
+	s.Filter = All
+	filter := c.Filter
+	if filter == nil {
+		filter = IsRoot
+	}
+	report := &Report{}
+	var index guidcheck.Index
+	var entities []Entity
+	for s.Scan() {
+		e := s.Entity()
+		if e.schema && !e.Root && e.HasGlobalId && ifcguid.IsValid(e.GlobalId) == nil {
+			report.Unexpected = append(report.Unexpected, e)
+		}
+		if !filter(&e) {
+			continue
+		}
+		if e.Root && !e.HasGlobalId {
+			report.Missing = append(report.Missing, e)
+			continue
+		}
+		report.Entities++
+		if err := ifcguid.IsValid(e.GlobalId); err != nil {
+			report.Invalid = append(report.Invalid, Invalid{Entity: e, Err: err})
+		}
+		index.Add(e.GlobalId)
+		entities = append(entities, e)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if schema := s.Schema(); schema != nil {
+		report.Schema = schema.Name
+	}
+	for _, g := range index.Duplicates() {
+		d := Duplicate{GlobalId: g.GlobalId}
+		for _, i := range g.Elements {
+			d.Entities = append(d.Entities, entities[i])
+		}
+		if rule, ok := c.suppress(d); ok {
+			d.Rule = rule
+			report.Suppressed = append(report.Suppressed, d)
+		} else {
+			report.Duplicates = append(report.Duplicates, d)
+		}
+	}
+	return report, nil
+}
+
+// suppress returns the name of the first rule that suppresses d.
+func (c *Checker) suppress(d Duplicate) (string, bool) {
+	types := make([]string, len(d.Entities))
+	for i, e := range d.Entities {
+		types[i] = e.Type
+	}
+	for _, rule := range c.Rules {
+		if (rule.Match != nil && rule.Match(d)) || guidcheck.AllTypes(rule.Types, types) {
+			return rule.Name, true
+		}
+	}
+	return "", false
+}
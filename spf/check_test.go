@@ -0,0 +1,147 @@
+package spf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _duplicatesFile = `ISO-10303-21;
+HEADER;
+FILE_SCHEMA(('IFC4'));
+ENDSEC;
+DATA;
+#5= IFCOWNERHISTORY(#1,#2,$,.NOCHANGE.,$,$,$,0);
+#10= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',#5,'Wall 1',$,$,$,$,$,$);
+#11= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',#5,'Wall 2',$,$,$,$,$,$);
+#12= IFCDOOR('2DWKyvjkf7PffFYiFUDNsy',#5,'Door',$,$,$,$,$,$,$,$,$,$);
+#13= IFCWINDOW('4DWKyvjkf7PffFYiFUDNsy',#5,'Window',$,$,$,$,$,$,$,$,$,$);
+#14= IFCSLAB('0mXQZaOVr7Tf$n6oIcHifF',#5,'Slab',$,$,$,$,$,$);
+#20= IFCRELASSOCIATESMATERIAL('3vB2YO$MX4xv5uCqZZG05x',#5,$,$,(#10),#30);
+#21= IFCRELASSOCIATESMATERIAL('3vB2YO$MX4xv5uCqZZG05x',#5,$,$,(#11),#31);
+#22= IfcRelAssociatesMaterial('4DWKyvjkf7PffFYiFUDNsy',#5,$,$,(#13),#31);
+ENDSEC;
+END-ISO-10303-21;
+`
+
+func Test_Check(t *testing.T) {
+	report, err := Check(strings.NewReader(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.True(t, report.Broken())
+	assert.Equal(t, 8, report.Entities)
+
+	if assert.Len(t, report.Duplicates, 2) {
+		d := report.Duplicates[0]
+		assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", d.GlobalId)
+		assert.Empty(t, d.Rule)
+		if assert.Len(t, d.Entities, 3) {
+			assert.Equal(t, uint64(10), d.Entities[0].Id)
+			assert.Equal(t, "IFCDOOR", d.Entities[2].Type)
+			assert.Equal(t, "Wall 2", d.Entities[1].Name)
+			assert.Equal(t, 8, d.Entities[1].Line)
+		}
+		// A material association doesn't suppress a duplicate that involves other types.
+		assert.Equal(t, "4DWKyvjkf7PffFYiFUDNsy", report.Duplicates[1].GlobalId)
+		assert.Len(t, report.Duplicates[1].Entities, 2)
+	}
+
+	if assert.Len(t, report.Suppressed, 1) {
+		assert.Equal(t, "3vB2YO$MX4xv5uCqZZG05x", report.Suppressed[0].GlobalId)
+		assert.Equal(t, "revit-material-associations", report.Suppressed[0].Rule)
+	}
+
+	// Invalid and duplicate GlobalIds are reported together.
+	if assert.Len(t, report.Invalid, 2) {
+		assert.Equal(t, uint64(13), report.Invalid[0].Id)
+		assert.Equal(t, uint64(22), report.Invalid[1].Id)
+		assert.Error(t, report.Invalid[0].Err)
+	}
+
+	var log bytes.Buffer
+	assert.NoError(t, report.WriteLog(&log))
+	lines := strings.Split(strings.TrimSpace(log.String()), "\n")
+	assert.Len(t, lines, 1+5+2+2)
+	assert.Equal(t, "Problem,GlobalId,Id,Type,Name,Line,Rule,Error", lines[0])
+	assert.Equal(t, "duplicate,2DWKyvjkf7PffFYiFUDNsy,#10,IFCWALL,Wall 1,7,,", lines[1])
+	assert.Equal(t, "suppressed,3vB2YO$MX4xv5uCqZZG05x,#20,IFCRELASSOCIATESMATERIAL,,12,revit-material-associations,", lines[6])
+	assert.True(t, strings.HasPrefix(lines[8], "invalid,4DWKyvjkf7PffFYiFUDNsy,#13,IFCWINDOW,Window,10,,"))
+}
+
+func Test_Checker_rules(t *testing.T) {
+	c := Checker{}
+	report, err := c.Check(strings.NewReader(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.Len(t, report.Duplicates, 3)
+	assert.Empty(t, report.Suppressed)
+
+	c.Rules = []Rule{{
+		Name: "doors-and-walls",
+		Match: func(d Duplicate) bool {
+			for _, e := range d.Entities {
+				if e.Type != "IFCWALL" && e.Type != "IFCDOOR" {
+					return false
+				}
+			}
+			return true
+		},
+	}}
+	report, err = c.Check(strings.NewReader(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.Len(t, report.Duplicates, 2)
+	if assert.Len(t, report.Suppressed, 1) {
+		assert.Equal(t, "doors-and-walls", report.Suppressed[0].Rule)
+	}
+
+	clean := "DATA;\n#1= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',$,$,$,$,$,$,$);\nENDSEC;"
+	report, err = Check(strings.NewReader(clean))
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+	assert.Equal(t, 1, report.Entities)
+
+	_, err = Check(strings.NewReader("DATA;\n#1= IFCWALL('2DWKyvjkf7PffFYiFUDNsy"))
+	assert.Error(t, err)
+}
+
+func Test_Check_schema(t *testing.T) {
+	const file = `ISO-10303-21;
+HEADER;
+FILE_SCHEMA(('IFC4X3_ADD2'));
+ENDSEC;
+DATA;
+#5= IFCOWNERHISTORY(#1,#2,$,.NOCHANGE.,$,$,$,0);
+#10= IFCALIGNMENT('2DWKyvjkf7PffFYiFUDNsy',#5,'Alignment',$,$,$,$,$);
+#11= IFCWALL($,#5,'Wall without GlobalId',$,$,$,$,$,$);
+#12= IFCPROPERTYSINGLEVALUE('0123456789012345678901',$,IFCLABEL('x'),$);
+#13= IFCPROPERTYSINGLEVALUE('Width',$,IFCLENGTHMEASURE(0.2),$);
+ENDSEC;
+END-ISO-10303-21;
+`
+	report, err := Check(strings.NewReader(file))
+	assert.NoError(t, err)
+	assert.Equal(t, "IFC4X3", report.Schema)
+	assert.Equal(t, 1, report.Entities)
+	assert.Empty(t, report.Invalid, "the missing GlobalId isn't reported as invalid too")
+	assert.Empty(t, report.Duplicates)
+	if assert.Len(t, report.Missing, 1) {
+		assert.Equal(t, uint64(11), report.Missing[0].Id)
+	}
+	if assert.Len(t, report.Unexpected, 1) {
+		assert.Equal(t, uint64(12), report.Unexpected[0].Id)
+	}
+	assert.True(t, report.Broken())
+
+	var log bytes.Buffer
+	assert.NoError(t, report.WriteLog(&log))
+	assert.Contains(t, log.String(), "\nmissing,,#11,IFCWALL,Wall without GlobalId,8,,\n")
+	assert.Contains(t, log.String(), "\nunexpected,0123456789012345678901,#12,IFCPROPERTYSINGLEVALUE,,9,,\n")
+
+	// Without a known schema, neither is reported.
+	report, err = Check(strings.NewReader(strings.Replace(file, "IFC4X3_ADD2", "IFC9", 1)))
+	assert.NoError(t, err)
+	assert.Empty(t, report.Schema)
+	assert.Empty(t, report.Missing)
+	assert.Empty(t, report.Unexpected)
+	assert.Equal(t, 2, report.Entities, "LooksLikeRoot selects the alignment and the property")
+}
@@ -0,0 +1,456 @@
+package spf
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/woweh/ifcguid/ifczip"
+)
+
+// Instance locates an entity in one of the files compared by Diff.
+type Instance struct {
+	// Id is the instance name, e.g. 42 for #42.
+	Id uint64 `json:"id"`
+	// Type is the entity type in upper case, e.g. "IFCWALL".
+	Type string `json:"type"`
+	// Line is the 1-based line number of the record.
+	Line int `json:"line"`
+}
+
+// Change is an entity that was added, removed or changed between two IFC-SPF files, see Diff.
+type Change struct {
+	// GlobalId is the GlobalId that matches the entities of both files.
+	GlobalId string `json:"globalId"`
+	// Type is the entity type in the new file, or in the old file if the entity was removed.
+	Type string `json:"type"`
+	// Name is the decoded Name attribute in the new file, or in the old file if the entity was removed.
+	Name string `json:"name,omitempty"`
+	// Old is the entity in the old file, or nil if it was added.
+	Old *Instance `json:"old,omitempty"`
+	// New is the entity in the new file, or nil if it was removed.
+	New *Instance `json:"new,omitempty"`
+}
+
+// DiffReport is the result of comparing two IFC-SPF files by GlobalId.
+type DiffReport struct {
+	// OldSchema and NewSchema are the names of the schemas of the files, or empty if they aren't known.
+	OldSchema string `json:"oldSchema,omitempty"`
+	NewSchema string `json:"newSchema,omitempty"`
+	// Added lists the entities of the new file whose GlobalId isn't in the old file, in file order.
+	Added []Change `json:"added"`
+	// Removed lists the entities of the old file whose GlobalId isn't in the new file, in file order.
+	Removed []Change `json:"removed"`
+	// Changed lists the entities whose attributes differ, in the order of the new file.
+	Changed []Change `json:"changed"`
+	// Unchanged is the number of entities whose attributes are the same in both files.
+	Unchanged int `json:"unchanged"`
+	// Ambiguous lists the GlobalIds used by more than one entity of either file, ordered by first use.
+	// Their entities can't be matched, so they aren't compared; see Check.
+	Ambiguous []string `json:"ambiguous"`
+}
+
+// Empty reports whether the files have the same entities with the same attributes, ignoring ambiguous GlobalIds.
+func (r *DiffReport) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Changed) == 0
+}
+
+// WriteJSON writes the report as indented JSON.
+func (r *DiffReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteSummary writes the report for humans: the number of changes by entity type,
+// followed by one line per change, prefixed with '+' for added, '-' for removed and '~' for changed entities.
+func (r *DiffReport) WriteSummary(w io.Writer) error {
+	if r.OldSchema != r.NewSchema {
+		fmt.Fprintf(w, "Schema: %s -> %s\n", schemaName(r.OldSchema), schemaName(r.NewSchema))
+	}
+	fmt.Fprintf(w, "%d added, %d removed, %d changed, %d unchanged", len(r.Added), len(r.Removed), len(r.Changed), r.Unchanged)
+	if len(r.Ambiguous) > 0 {
+		fmt.Fprintf(w, ", %d ambiguous GlobalIds not compared", len(r.Ambiguous))
+	}
+	_, err := fmt.Fprintln(w)
+	if err != nil || r.Empty() {
+		return err
+	}
+
+	type counts struct{ added, removed, changed int }
+	byType := map[string]*counts{}
+	count := func(changes []Change, field func(c *counts) *int) {
+		for _, c := range changes {
+			if byType[c.Type] == nil {
+				byType[c.Type] = &counts{}
+			}
+			*field(byType[c.Type])++
+		}
+	}
+	count(r.Added, func(c *counts) *int { return &c.added })
+	count(r.Removed, func(c *counts) *int { return &c.removed })
+	count(r.Changed, func(c *counts) *int { return &c.changed })
+	types := make([]string, 0, len(byType))
+	width := len("Type")
+	for t := range byType {
+		types = append(types, t)
+		width = max(width, len(t))
+	}
+	sort.Strings(types)
+	fmt.Fprintf(w, "\n%-*s  Added  Removed  Changed\n", width, "Type")
+	for _, t := range types {
+		c := byType[t]
+		fmt.Fprintf(w, "%-*s  %5d  %7d  %7d\n", width, t, c.added, c.removed, c.changed)
+	}
+
+	fmt.Fprintln(w)
+	line := func(prefix string, c Change) {
+		ids := ""
+		switch {
+		case c.Old == nil:
+			ids = "#" + strconv.FormatUint(c.New.Id, 10)
+		case c.New == nil:
+			ids = "#" + strconv.FormatUint(c.Old.Id, 10)
+		default:
+			ids = "#" + strconv.FormatUint(c.Old.Id, 10) + " -> #" + strconv.FormatUint(c.New.Id, 10)
+		}
+		typ := c.Type
+		if c.Old != nil && c.New != nil && c.Old.Type != c.New.Type {
+			typ = c.Old.Type + " -> " + c.New.Type
+		}
+		fmt.Fprintf(w, "%s %s %s %s", prefix, c.GlobalId, typ, ids)
+		if c.Name != "" {
+			fmt.Fprintf(w, " %q", c.Name)
+		}
+		fmt.Fprintln(w)
+	}
+	for _, c := range r.Added {
+		line("+", c)
+	}
+	for _, c := range r.Removed {
+		line("-", c)
+	}
+	for _, c := range r.Changed {
+		line("~", c)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// schemaName returns the name of a schema for WriteSummary.
+func schemaName(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return name
+}
+
+// Differ compares two IFC-SPF files by GlobalId.
+type Differ struct {
+	// Filter selects the entities that are matched by GlobalId. If nil, IsRoot is used.
+	Filter Filter
+	// OwnerHistory makes changes of the OwnerHistory attribute count.
+	// By default, it is ignored, since most applications update the owner history on every export.
+	OwnerHistory bool
+}
+
+// Diff compares the IFC-SPF files read from oldFile and newFile by GlobalId, see Differ.Diff.
+func Diff(oldFile, newFile io.Reader) (*DiffReport, error) {
+	d := Differ{}
+	return d.Diff(oldFile, newFile)
+}
+
+// DiffFiles compares the IFC-SPF files with the given names by GlobalId, see Differ.Diff.
+// The files may be .ifczip archives, see ifczip.OpenFile.
+func DiffFiles(oldName, newName string) (*DiffReport, error) {
+	d := Differ{}
+	return d.DiffFiles(oldName, newName)
+}
+
+// DiffFiles is like Diff, but reads the files with the given names, which may be .ifczip archives.
+func (d *Differ) DiffFiles(oldName, newName string) (*DiffReport, error) {
+	oldFile, err := ifczip.OpenFile(oldName, ifczip.FormatSpf)
+	if err != nil {
+		return nil, err
+	}
+	defer oldFile.Close()
+	newFile, err := ifczip.OpenFile(newName, ifczip.FormatSpf)
+	if err != nil {
+		return nil, err
+	}
+	defer newFile.Close()
+	return d.Diff(oldFile, newFile)
+}
+
+// Diff compares the IFC-SPF files read from oldFile and newFile by GlobalId.
+//
+// The entities selected by the Filter are matched by their GlobalId, and compared by a hash of their attributes.
+// References to other selected entities are hashed as their GlobalIds, and references to any other entity,
+// e.g. a placement or a representation, as the hash of that entity, recursively.
+// So renumbered instances don't count as changes, but a moved placement or a new geometry changes the element
+// that references it. Strings are compared after decoding, numbers by value,
+// and white space and comments are ignored.
+//
+// Unlike Check, Diff keeps both files in memory.
+func (d *Differ) Diff(oldFile, newFile io.Reader) (*DiffReport, error) {
+	filter := d.Filter
+	if filter == nil {
+		filter = IsRoot
+	}
+	oldModel, err := readModel(oldFile, filter, d.OwnerHistory)
+	if err != nil {
+		return nil, fmt.Errorf("old file: %w", err)
+	}
+	newModel, err := readModel(newFile, filter, d.OwnerHistory)
+	if err != nil {
+		return nil, fmt.Errorf("new file: %w", err)
+	}
+	report := &DiffReport{
+		OldSchema: oldModel.schema,
+		NewSchema: newModel.schema,
+		Added:     []Change{},
+		Removed:   []Change{},
+		Changed:   []Change{},
+		Ambiguous: []string{},
+	}
+	ambiguous := map[string]bool{}
+	for _, m := range []*model{oldModel, newModel} {
+		for _, e := range m.keyed {
+			if len(m.byGlobalId[e.GlobalId]) > 1 && !ambiguous[e.GlobalId] {
+				ambiguous[e.GlobalId] = true
+				report.Ambiguous = append(report.Ambiguous, e.GlobalId)
+			}
+		}
+	}
+
+	for _, e := range newModel.keyed {
+		if ambiguous[e.GlobalId] {
+			continue
+		}
+		c := Change{GlobalId: e.GlobalId, Type: e.Type, Name: e.Name, New: newInstance(e)}
+		ids, ok := oldModel.byGlobalId[e.GlobalId]
+		if !ok {
+			report.Added = append(report.Added, c)
+			continue
+		}
+		old := oldModel.keyed[ids[0]]
+		if oldModel.hash(old.Id) == newModel.hash(e.Id) {
+			report.Unchanged++
+			continue
+		}
+		c.Old = newInstance(old)
+		report.Changed = append(report.Changed, c)
+	}
+	for _, e := range oldModel.keyed {
+		if _, ok := newModel.byGlobalId[e.GlobalId]; !ok && !ambiguous[e.GlobalId] {
+			report.Removed = append(report.Removed, Change{GlobalId: e.GlobalId, Type: e.Type, Name: e.Name, Old: newInstance(e)})
+		}
+	}
+	return report, nil
+}
+
+// newInstance returns the Instance of e.
+func newInstance(e Entity) *Instance {
+	return &Instance{Id: e.Id, Type: e.Type, Line: e.Line}
+}
+
+// model is an IFC-SPF file read into memory by readModel.
+type model struct {
+	schema string
+	// records holds the canonical form of every entity instance, by instance name.
+	records map[uint64]*record
+	// keyed lists the entities selected by the filter that have a GlobalId, in file order.
+	keyed []Entity
+	// byIndex finds entities in keyed by instance name.
+	byIndex map[uint64]int
+	// byGlobalId finds entities in keyed by GlobalId; there is more than one for duplicate GlobalIds.
+	byGlobalId map[string][]int
+	// hashes caches the hashes of records that aren't part of a reference cycle, see hashAt.
+	hashes map[uint64][sha256.Size]byte
+	// hashing holds the depth of the records that are being hashed.
+	hashing map[uint64]int
+}
+
+// record is the canonical form of an entity instance: its tokens after the '=', normalized,
+// with the references left out. References are between parts, i.e. parts[i] is followed by refs[i].
+type record struct {
+	parts []string
+	refs  []uint64
+}
+
+// readModel reads every entity instance of the IFC-SPF file read from r into memory.
+// The entities selected by filter that have a GlobalId are keyed; unless ownerHistory is set,
+// their second attribute is left out of their canonical form.
+func readModel(r io.Reader, filter Filter, ownerHistory bool) (*model, error) {
+	s := NewScanner(r)
+	s.Filter = All
+	s.keepTokens = true
+	m := &model{
+		records:    map[uint64]*record{},
+		byGlobalId: map[string][]int{},
+		hashes:     map[uint64][sha256.Size]byte{},
+		hashing:    map[uint64]int{},
+	}
+	for s.Scan() {
+		e := s.Entity()
+		keyed := filter(&e) && e.HasGlobalId && e.GlobalId != ""
+		rec, err := canonicalize(s.tokens, keyed && !ownerHistory)
+		if err != nil {
+			return nil, fmt.Errorf("#%d: %w", e.Id, err)
+		}
+		if _, ok := m.records[e.Id]; ok {
+			return nil, fmt.Errorf("line %d: #%d is defined more than once", e.Line, e.Id)
+		}
+		m.records[e.Id] = rec
+		if keyed {
+			m.byGlobalId[e.GlobalId] = append(m.byGlobalId[e.GlobalId], len(m.keyed))
+			m.keyed = append(m.keyed, e)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if schema := s.Schema(); schema != nil {
+		m.schema = schema.Name
+	}
+	m.byIndex = make(map[uint64]int, len(m.keyed))
+	for i, e := range m.keyed {
+		m.byIndex[e.Id] = i
+	}
+	return m, nil
+}
+
+// canonicalize returns the canonical form of the tokens of a record, from its '=' to its ';'.
+// If skipOwnerHistory is set, the second attribute of a simple instance is replaced by '*'.
+func canonicalize(tokens []token, skipOwnerHistory bool) (*record, error) {
+	if len(tokens) > 0 && tokens[0].kind == tokEquals {
+		tokens = tokens[1:]
+	}
+	if n := len(tokens); n > 0 && tokens[n-1].kind == tokSemicolon {
+		tokens = tokens[:n-1]
+	}
+	simple := len(tokens) > 0 && tokens[0].kind == tokWord
+	rec := &record{}
+	var b strings.Builder
+	depth, attr := 0, 0
+	for _, tok := range tokens {
+		if simple && skipOwnerHistory && depth == 1 && attr == 1 && (tok.kind == tokRef || tok.kind == tokDollar) {
+			b.WriteByte('*')
+			continue
+		}
+		switch tok.kind {
+		case tokOpen:
+			depth++
+			b.WriteByte('(')
+		case tokClose:
+			depth--
+			b.WriteByte(')')
+		case tokComma:
+			if depth == 1 {
+				attr++
+			}
+			b.WriteByte(',')
+		case tokRef:
+			id, err := strconv.ParseUint(tok.text[1:], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid instance reference %q", tok.line, tok.text)
+			}
+			rec.parts = append(rec.parts, b.String())
+			rec.refs = append(rec.refs, id)
+			b.Reset()
+		case tokString:
+			value, err := decodeString(tok.text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", tok.line, err)
+			}
+			b.WriteString(strconv.Quote(value))
+		case tokWord:
+			b.WriteString(canonicalWord(tok.text))
+		default:
+			b.WriteString(tok.text)
+		}
+	}
+	rec.parts = append(rec.parts, b.String())
+	return rec, nil
+}
+
+// canonicalWord returns the canonical form of a word: numbers by value, e.g. "0." and "0.0" as "0",
+// and everything else, e.g. entity types and enumerations, in upper case.
+func canonicalWord(word string) string {
+	if c := word[0]; c == '-' || c == '+' || c == '.' || (c >= '0' && c <= '9') {
+		if f, err := strconv.ParseFloat(word, 64); err == nil {
+			if f == 0 {
+				// -0. is written by some exporters.
+				f = 0
+			}
+			return strconv.FormatFloat(f, 'g', -1, 64)
+		}
+	}
+	return strings.ToUpper(word)
+}
+
+// hash returns the hash of the record of the entity with the given instance name.
+// References to keyed entities are hashed as their GlobalId, and references to other entities as their hash.
+// References to missing instances are hashed as a marker.
+func (m *model) hash(id uint64) [sha256.Size]byte {
+	sum, _ := m.hashAt(id, 0)
+	return sum
+}
+
+// hashAt hashes the record of the entity with the given instance name, at the given depth of the references followed.
+// A reference that forms a cycle is hashed as the number of references followed since the entity it refers to,
+// so the hash of a cycle depends only on the entity through which it is entered.
+//
+// It returns the hash, and the smallest depth that a reference forming a cycle refers to, or math.MaxInt if there is none.
+// Only the hashes of records that aren't part of a cycle are cached: the hash of a record in a cycle differs
+// depending on the record through which the cycle was entered.
+func (m *model) hashAt(id uint64, depth int) ([sha256.Size]byte, int) {
+	if h, ok := m.hashes[id]; ok {
+		return h, math.MaxInt
+	}
+	rec := m.records[id]
+	m.hashing[id] = depth
+	defer delete(m.hashing, id)
+	low := math.MaxInt
+	h := sha256.New()
+	for i, part := range rec.parts {
+		io.WriteString(h, part)
+		if i == len(rec.refs) {
+			break
+		}
+		ref := rec.refs[i]
+		if d, ok := m.hashing[ref]; ok {
+			io.WriteString(h, "#cycle"+strconv.Itoa(depth-d))
+			low = min(low, d)
+			continue
+		}
+		switch {
+		case m.records[ref] == nil:
+			io.WriteString(h, "#?")
+		case m.isKeyed(ref):
+			io.WriteString(h, "#"+strconv.Quote(m.keyed[m.byIndex[ref]].GlobalId))
+		default:
+			sum, refLow := m.hashAt(ref, depth+1)
+			low = min(low, refLow)
+			io.WriteString(h, "#"+hex.EncodeToString(sum[:]))
+		}
+	}
+	var sum [sha256.Size]byte
+	h.Sum(sum[:0])
+	if low > depth {
+		m.hashes[id] = sum
+	}
+	return sum, low
+}
+
+// isKeyed reports whether the entity with the given instance name is keyed by its GlobalId.
+func (m *model) isKeyed(id uint64) bool {
+	_, ok := m.byIndex[id]
+	return ok
+}
@@ -0,0 +1,247 @@
+package spf
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _oldModel = `ISO-10303-21;
+HEADER;
+FILE_SCHEMA(('IFC4'));
+ENDSEC;
+DATA;
+#5= IFCOWNERHISTORY(#1,#2,$,.ADDED.,$,$,$,1700000000);
+#10= IFCPROJECT('0YvctVUKr0kugbFTf53O9L',#5,'Project',$,$,$,$,$,$);
+#20= IFCCARTESIANPOINT((0.,0.,0.));
+#21= IFCAXIS2PLACEMENT3D(#20,$,$);
+#22= IFCLOCALPLACEMENT($,#21);
+#42= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',#5,'Wall',$,$,#22,$,'316435',$);
+#43= IFCDOOR('0mXQZaOVr7Tf$n6oIcHifF',#5,'Door',$,$,#22,$,$,$,$,$,$,$);
+#44= IFCSLAB('3vB2YO$MX4xv5uCqZZG05x',#5,'Slab',$,$,#22,$,$,$);
+#50= IFCRELCONTAINEDINSPATIALSTRUCTURE('1hfwn1GGL0n9eyWwkxFc8T',#5,$,$,(#42,#43,#44),#10);
+ENDSEC;
+END-ISO-10303-21;
+`
+
+// _newModel is _oldModel exported again: renumbered, reformatted, with a new owner history,
+// the door replaced by a window, and the slab moved.
+const _newModel = `ISO-10303-21;
+HEADER;
+FILE_SCHEMA(('IFC4'));
+ENDSEC;
+DATA;
+#1005= IFCOWNERHISTORY(#1001,#1002,$,.MODIFIED.,1800000000,#1001,#1002,1700000000);
+#1010= IFCPROJECT('0YvctVUKr0kugbFTf53O9L',#1005,'Project',$,$,$,$,$,$);
+/* The wall is unchanged, apart from its placement being renumbered. */
+#1020= IFCCARTESIANPOINT((0.0,0.0,0.0));
+#1021= IFCAXIS2PLACEMENT3D(#1020,$,$);
+#1022= IFCLOCALPLACEMENT($,#1021);
+#1030= IFCCARTESIANPOINT((1.E3,0.,0.));
+#1031= IFCAXIS2PLACEMENT3D(#1030,$,$);
+#1032= IFCLOCALPLACEMENT($,#1031);
+#1042= IFCWALL('2DWKyvjkf7PffFYiFUDNsy', #1005, 'Wall', $, $, #1022, $, '316435', $);
+#1044= IFCSLAB('3vB2YO$MX4xv5uCqZZG05x',#1005,'Slab',$,$,#1032,$,$,$);
+#1045= IFCWINDOW('4DWKyvjkf7PffFYiFUDNsy',#1005,'Window',$,$,#1022,$,$,$,$,$,$,$);
+#1050= IFCRELCONTAINEDINSPATIALSTRUCTURE('1hfwn1GGL0n9eyWwkxFc8T',#1005,$,$,(#1042,#1044,#1045),#1010);
+ENDSEC;
+END-ISO-10303-21;
+`
+
+func Test_Diff(t *testing.T) {
+	report, err := Diff(strings.NewReader(_oldModel), strings.NewReader(_newModel))
+	assert.NoError(t, err)
+	assert.Equal(t, "IFC4", report.OldSchema)
+	assert.Equal(t, "IFC4", report.NewSchema)
+	assert.False(t, report.Empty())
+	assert.Equal(t, 2, report.Unchanged, "the project and the wall")
+	assert.Empty(t, report.Ambiguous)
+
+	assert.Equal(t, []Change{{
+		GlobalId: "4DWKyvjkf7PffFYiFUDNsy", Type: "IFCWINDOW", Name: "Window",
+		New: &Instance{Id: 1045, Type: "IFCWINDOW", Line: 17},
+	}}, report.Added)
+	assert.Equal(t, []Change{{
+		GlobalId: "0mXQZaOVr7Tf$n6oIcHifF", Type: "IFCDOOR", Name: "Door",
+		Old: &Instance{Id: 43, Type: "IFCDOOR", Line: 12},
+	}}, report.Removed)
+	if assert.Len(t, report.Changed, 2) {
+		assert.Equal(t, Change{
+			GlobalId: "3vB2YO$MX4xv5uCqZZG05x", Type: "IFCSLAB", Name: "Slab",
+			Old: &Instance{Id: 44, Type: "IFCSLAB", Line: 13},
+			New: &Instance{Id: 1044, Type: "IFCSLAB", Line: 16},
+		}, report.Changed[0])
+		assert.Equal(t, "1hfwn1GGL0n9eyWwkxFc8T", report.Changed[1].GlobalId)
+	}
+
+	// Comparing a file with itself, or with a renumbered copy, finds no changes.
+	report, err = Diff(strings.NewReader(_newModel), strings.NewReader(_newModel))
+	assert.NoError(t, err)
+	assert.True(t, report.Empty())
+	assert.Equal(t, 5, report.Unchanged)
+	renumbered := strings.NewReplacer("#1020", "#7", "#1021", "#8", "#1022", "#9").Replace(_newModel)
+	report, err = Diff(strings.NewReader(_newModel), strings.NewReader(renumbered))
+	assert.NoError(t, err)
+	assert.True(t, report.Empty())
+}
+
+func Test_Diff_values(t *testing.T) {
+	wall := func(attrs string) string {
+		return strings.Replace(_oldModel, "'Wall',$,$,#22,$,'316435',$", attrs, 1)
+	}
+	tests := []struct {
+		name    string
+		attrs   string
+		changed bool
+	}{
+		{"same", "'Wall',$,$,#22,$,'316435',$", false},
+		{"white space", "'Wall' , $ ,$,\n#22,$,'316435',$", false},
+		{"encoded string", `'\X\57all',$,$,#22,$,'316435',$`, false},
+		{"enumeration case", "'Wall',$,$,#22,$,'316435',.standard.", true},
+		{"name", "'Wall 2',$,$,#22,$,'316435',$", true},
+		{"unset", "'Wall',$,$,$,$,'316435',$", true},
+		{"reference to a GlobalId", "'Wall',$,$,#22,#10,'316435',$", true},
+		{"missing reference", "'Wall',$,$,#23,$,'316435',$", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Diff(strings.NewReader(_oldModel), strings.NewReader(wall(tt.attrs)))
+			assert.NoError(t, err)
+			if tt.changed {
+				if assert.Len(t, report.Changed, 1) {
+					assert.Equal(t, uint64(42), report.Changed[0].New.Id)
+				}
+			} else {
+				assert.True(t, report.Empty(), "%+v", report.Changed)
+			}
+		})
+	}
+
+	// Numbers are compared by value, and enumerations ignoring case.
+	model := strings.Replace(_oldModel, "(0.,0.,0.)", "(0.0E0,-0.,0)", 1)
+	model = strings.Replace(model, ".ADDED.", ".added.", 1)
+	report, err := Diff(strings.NewReader(_oldModel), strings.NewReader(model))
+	assert.NoError(t, err)
+	assert.True(t, report.Empty(), "%+v", report.Changed)
+}
+
+func Test_Differ_OwnerHistory(t *testing.T) {
+	model := strings.Replace(_oldModel, "1700000000);", "1800000000);", 1)
+	report, err := Diff(strings.NewReader(_oldModel), strings.NewReader(model))
+	assert.NoError(t, err)
+	assert.True(t, report.Empty())
+
+	d := Differ{OwnerHistory: true}
+	report, err = d.Diff(strings.NewReader(_oldModel), strings.NewReader(model))
+	assert.NoError(t, err)
+	assert.Len(t, report.Changed, 5)
+	assert.Zero(t, report.Unchanged)
+}
+
+func Test_Diff_ambiguous(t *testing.T) {
+	model := strings.Replace(_oldModel, "'3vB2YO$MX4xv5uCqZZG05x',#5,'Slab'", "'2DWKyvjkf7PffFYiFUDNsy',#5,'Slab 2'", 1)
+	report, err := Diff(strings.NewReader(_oldModel), strings.NewReader(model))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"2DWKyvjkf7PffFYiFUDNsy"}, report.Ambiguous)
+	if assert.Len(t, report.Removed, 1) {
+		assert.Equal(t, "3vB2YO$MX4xv5uCqZZG05x", report.Removed[0].GlobalId)
+	}
+	assert.Empty(t, report.Added)
+	// The relationship references the slab, which now has the GlobalId of the wall.
+	assert.Len(t, report.Changed, 1)
+
+	_, err = Diff(strings.NewReader(_oldModel), strings.NewReader(strings.Replace(_newModel, "#1020=", "#1010=", 1)))
+	assert.ErrorContains(t, err, "new file: line 9: #1010 is defined more than once")
+}
+
+func Test_Diff_reference_cycle(t *testing.T) {
+	// The wall and the slab reference different entities of the same reference cycle.
+	model := func(records ...string) string {
+		return "ISO-10303-21;\nHEADER;\nFILE_SCHEMA(('IFC4'));\nENDSEC;\nDATA;\n" +
+			strings.Join(records, "\n") + "\nENDSEC;\nEND-ISO-10303-21;\n"
+	}
+	wall := "#42= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',$,'Wall',$,$,$,#60,$,$);"
+	slab := "#44= IFCSLAB('3vB2YO$MX4xv5uCqZZG05x',$,'Slab',$,$,$,#61,$,$);"
+	first := "#60= IFCFACEOUTERBOUND(#61,.T.);"
+	second := "#61= IFCFACEBOUND(#60,.F.);"
+	oldModel := model(wall, slab, first, second)
+
+	// The result doesn't depend on the order in which the entities, and thus the cycle, are visited.
+	for _, newModel := range []string{
+		oldModel,
+		model(slab, wall, second, first),
+		model(second, first, slab, wall),
+		strings.NewReplacer("#60", "#7", "#61", "#8").Replace(model(slab, wall, first, second)),
+	} {
+		report, err := Diff(strings.NewReader(oldModel), strings.NewReader(newModel))
+		assert.NoError(t, err)
+		assert.True(t, report.Empty(), "%+v", report.Changed)
+		assert.Equal(t, 2, report.Unchanged)
+	}
+
+	// The hash of an entity in a cycle doesn't depend on the entity through which the cycle was entered.
+	entered, err := readModel(strings.NewReader(oldModel), All, false)
+	assert.NoError(t, err)
+	entered.hash(61)
+	fresh, err := readModel(strings.NewReader(oldModel), All, false)
+	assert.NoError(t, err)
+	assert.Equal(t, fresh.hash(60), entered.hash(60))
+
+	// A change anywhere in the cycle changes both entities.
+	report, err := Diff(strings.NewReader(oldModel), strings.NewReader(model(slab, wall, first, strings.Replace(second, ".F.", ".T.", 1))))
+	assert.NoError(t, err)
+	assert.Len(t, report.Changed, 2)
+
+	// Cycles of different lengths are different.
+	report, err = Diff(strings.NewReader(oldModel), strings.NewReader(model(wall, slab, "#60= IFCFACEOUTERBOUND(#60,.T.);", second)))
+	assert.NoError(t, err)
+	assert.Len(t, report.Changed, 2)
+}
+
+func Test_DiffReport_WriteSummary(t *testing.T) {
+	report, err := Diff(strings.NewReader(_oldModel), strings.NewReader(_newModel))
+	assert.NoError(t, err)
+	var summary bytes.Buffer
+	assert.NoError(t, report.WriteSummary(&summary))
+	assert.Equal(t, `1 added, 1 removed, 2 changed, 2 unchanged
+
+Type                               Added  Removed  Changed
+IFCDOOR                                0        1        0
+IFCRELCONTAINEDINSPATIALSTRUCTURE      0        0        1
+IFCSLAB                                0        0        1
+IFCWINDOW                              1        0        0
+
++ 4DWKyvjkf7PffFYiFUDNsy IFCWINDOW #1045 "Window"
+- 0mXQZaOVr7Tf$n6oIcHifF IFCDOOR #43 "Door"
+~ 3vB2YO$MX4xv5uCqZZG05x IFCSLAB #44 -> #1044 "Slab"
+~ 1hfwn1GGL0n9eyWwkxFc8T IFCRELCONTAINEDINSPATIALSTRUCTURE #50 -> #1050
+
+`, summary.String())
+
+	summary.Reset()
+	report, err = Diff(strings.NewReader(_oldModel), strings.NewReader(_oldModel))
+	assert.NoError(t, err)
+	assert.NoError(t, report.WriteSummary(&summary))
+	assert.Equal(t, "0 added, 0 removed, 0 changed, 5 unchanged\n", summary.String())
+}
+
+func Test_DiffReport_WriteJSON(t *testing.T) {
+	report, err := Diff(strings.NewReader(_oldModel), strings.NewReader(_newModel))
+	assert.NoError(t, err)
+	var out bytes.Buffer
+	assert.NoError(t, report.WriteJSON(&out))
+	assert.Contains(t, out.String(), `"globalId": "4DWKyvjkf7PffFYiFUDNsy"`)
+
+	var decoded DiffReport
+	assert.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	assert.Equal(t, *report, decoded)
+
+	report, err = Diff(strings.NewReader(_oldModel), strings.NewReader(_oldModel))
+	assert.NoError(t, err)
+	out.Reset()
+	assert.NoError(t, report.WriteJSON(&out))
+	assert.Contains(t, out.String(), `"added": [],`, "empty lists aren't null")
+}
@@ -0,0 +1,226 @@
+// Package express reads the entity declarations of EXPRESS schemas (ISO 10303-11), like the IFC schemas
+// published by buildingSMART, as far as needed to find the IfcRoot subtypes and the positions of their attributes.
+package express
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// _subtypeOf finds the supertypes in the header of an entity declaration.
+var _subtypeOf = regexp.MustCompile(`(?i)\bSUBTYPE\s+OF\s*\(([^)]*)\)`)
+
+// _sectionKeywords start the sections of an entity declaration that follow the explicit attributes.
+var _sectionKeywords = map[string]bool{"DERIVE": true, "INVERSE": true, "UNIQUE": true, "WHERE": true}
+
+// Schema is an EXPRESS schema.
+type Schema struct {
+	// Name is the name of the schema, e.g. "IFC4".
+	Name string
+	// Entities holds the entity declarations, by upper case name.
+	Entities map[string]*Entity
+}
+
+// Entity is an entity declaration.
+type Entity struct {
+	// Name is the name of the entity, as declared, e.g. "IfcWall".
+	Name string
+	// Supertypes lists the names of the direct supertypes.
+	Supertypes []string
+	// Attributes lists the names of the explicit attributes declared by the entity itself, in order.
+	// Redeclared attributes of supertypes, i.e. SELF\IfcRoot.Name, aren't included.
+	Attributes []string
+}
+
+// Parse reads the entity declarations of an EXPRESS schema.
+// Types, functions and rules are skipped; comments may appear anywhere.
+func Parse(r io.Reader) (*Schema, error) {
+	data, err := io.ReadAll(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+	s := &Schema{Entities: map[string]*Entity{}}
+	var e *Entity
+	explicit := false
+	for _, stmt := range statements(string(data)) {
+		words := strings.Fields(stmt)
+		if len(words) == 0 {
+			continue
+		}
+		keyword := strings.ToUpper(words[0])
+		switch {
+		case keyword == "SCHEMA" && len(words) > 1 && s.Name == "":
+			s.Name = words[1]
+		case keyword == "ENTITY" && len(words) > 1:
+			if e != nil {
+				return nil, fmt.Errorf("entity %s: END_ENTITY missing", e.Name)
+			}
+			e = &Entity{Name: words[1]}
+			if m := _subtypeOf.FindStringSubmatch(stmt); m != nil {
+				for _, name := range strings.Split(m[1], ",") {
+					e.Supertypes = append(e.Supertypes, strings.TrimSpace(name))
+				}
+			}
+			explicit = true
+		case keyword == "END_ENTITY":
+			if e == nil {
+				return nil, fmt.Errorf("END_ENTITY without ENTITY")
+			}
+			s.Entities[strings.ToUpper(e.Name)] = e
+			e = nil
+		case e != nil && explicit:
+			if _sectionKeywords[keyword] {
+				explicit = false
+				continue
+			}
+			names, _, ok := strings.Cut(stmt, ":")
+			if !ok {
+				return nil, fmt.Errorf("entity %s: invalid attribute %q", e.Name, strings.Join(words, " "))
+			}
+			for _, name := range strings.Split(names, ",") {
+				if name = strings.TrimSpace(name); !strings.HasPrefix(strings.ToUpper(name), `SELF\`) {
+					e.Attributes = append(e.Attributes, name)
+				}
+			}
+		}
+	}
+	if e != nil {
+		return nil, fmt.Errorf("entity %s: END_ENTITY missing", e.Name)
+	}
+	if s.Name == "" {
+		return nil, fmt.Errorf("SCHEMA declaration missing")
+	}
+	for _, e := range s.Entities {
+		for _, name := range e.Supertypes {
+			if s.Entities[strings.ToUpper(name)] == nil {
+				return nil, fmt.Errorf("entity %s: unknown supertype %s", e.Name, name)
+			}
+		}
+	}
+	return s, nil
+}
+
+// statements splits the source of a schema into statements at semicolons, without comments.
+// Semicolons in comments and string literals don't end a statement.
+func statements(src string) []string {
+	var result []string
+	var b strings.Builder
+	for i := 0; i < len(src); i++ {
+		switch c := src[i]; {
+		case c == '(' && i+1 < len(src) && src[i+1] == '*':
+			end := strings.Index(src[i+2:], "*)")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += 2 + end + 1
+			}
+			b.WriteByte(' ')
+		case c == '-' && i+1 < len(src) && src[i+1] == '-':
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end
+			}
+			b.WriteByte(' ')
+		case c == '\'':
+			end := strings.IndexByte(src[i+1:], '\'')
+			if end < 0 {
+				end = len(src) - i - 1
+			}
+			b.WriteString(src[i : i+1+end+1])
+			i += end + 1
+		case c == ';':
+			result = append(result, b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return append(result, b.String())
+}
+
+// Attributes returns the names of the explicit attributes of an entity, including inherited ones,
+// in the order in which they are written in IFC-SPF: the attributes of the supertypes first.
+func (s *Schema) Attributes(entity string) []string {
+	e := s.Entities[strings.ToUpper(entity)]
+	if e == nil {
+		return nil
+	}
+	var result []string
+	for _, name := range e.Supertypes {
+		result = append(result, s.Attributes(name)...)
+	}
+	return append(result, e.Attributes...)
+}
+
+// AttributePosition returns the 0-based position of an explicit attribute of an entity, including inherited ones.
+func (s *Schema) AttributePosition(entity, attribute string) (int, bool) {
+	for i, name := range s.Attributes(entity) {
+		if strings.EqualFold(name, attribute) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// Subtypes returns the names of the entity and all its direct and indirect subtypes, sorted.
+func (s *Schema) Subtypes(entity string) []string {
+	var result []string
+	for _, e := range s.Entities {
+		if s.IsSubtype(e.Name, entity) {
+			result = append(result, e.Name)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
+
+// IsSubtype reports whether entity is supertype, or a direct or indirect subtype of it.
+func (s *Schema) IsSubtype(entity, supertype string) bool {
+	if strings.EqualFold(entity, supertype) {
+		return true
+	}
+	e := s.Entities[strings.ToUpper(entity)]
+	if e == nil {
+		return false
+	}
+	for _, name := range e.Supertypes {
+		if s.IsSubtype(name, supertype) {
+			return true
+		}
+	}
+	return false
+}
+
+// RootTypes returns the IfcRoot subtypes of an IFC schema, including IfcRoot, by upper case name.
+// The scanners of IFC-SPF files read the GlobalId from the first attribute of these types,
+// so it is an error if it isn't the first attribute of one of them.
+func RootTypes(s *Schema) (map[string]bool, error) {
+	types := map[string]bool{}
+	for _, name := range s.Subtypes("IfcRoot") {
+		pos, ok := s.AttributePosition(name, "GlobalId")
+		if !ok {
+			return nil, fmt.Errorf("%s has no GlobalId attribute", name)
+		}
+		if pos != 0 {
+			return nil, fmt.Errorf("the GlobalId of %s is attribute %d, not the first one", name, pos)
+		}
+		types[strings.ToUpper(name)] = true
+	}
+	if len(types) == 0 {
+		return nil, fmt.Errorf("schema %s has no IfcRoot entity", s.Name)
+	}
+	return types, nil
+}
+
+// MergeTypes adds the types of base to types.
+func MergeTypes(types, base map[string]bool) {
+	for name := range base {
+		types[name] = true
+	}
+}
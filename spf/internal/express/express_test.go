@@ -0,0 +1,91 @@
+package express
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Parse(t *testing.T) {
+	f, err := os.Open("testdata/excerpt.exp")
+	assert.NoError(t, err)
+	defer f.Close()
+	s, err := Parse(f)
+	assert.NoError(t, err)
+	assert.Equal(t, "IFC4", s.Name)
+	assert.Len(t, s.Entities, 11)
+
+	wall := s.Entities["IFCWALL"]
+	if assert.NotNil(t, wall) {
+		assert.Equal(t, "IfcWall", wall.Name)
+		assert.Equal(t, []string{"IfcElement"}, wall.Supertypes)
+		assert.Equal(t, []string{"PredefinedType"}, wall.Attributes)
+	}
+	assert.Equal(t, []string{"RelatedObjects", "RelatingPropertyDefinition"}, s.Entities["IFCRELDEFINESBYPROPERTIES"].Attributes,
+		"derived redeclarations aren't explicit attributes")
+	assert.Equal(t, []string{"Coordinates", "Extra"}, s.Entities["IFCCARTESIANPOINT"].Attributes)
+	assert.Empty(t, s.Entities["IFCOBJECTDEFINITION"].Attributes, "inverse attributes aren't explicit attributes")
+
+	assert.Equal(t, []string{
+		"GlobalId", "OwnerHistory", "Name", "Description", "ObjectType", "ObjectPlacement", "Representation", "Tag", "PredefinedType",
+	}, s.Attributes("IfcWallStandardCase"))
+	pos, ok := s.AttributePosition("IFCWALLSTANDARDCASE", "GlobalId")
+	assert.True(t, ok)
+	assert.Zero(t, pos)
+	pos, ok = s.AttributePosition("IfcWall", "tag")
+	assert.True(t, ok)
+	assert.Equal(t, 7, pos)
+	_, ok = s.AttributePosition("IfcCartesianPoint", "GlobalId")
+	assert.False(t, ok)
+
+	assert.Equal(t, []string{
+		"IfcElement", "IfcObject", "IfcObjectDefinition", "IfcProduct", "IfcRelDefinesByProperties",
+		"IfcRelationship", "IfcRoot", "IfcWall", "IfcWallStandardCase",
+	}, s.Subtypes("IfcRoot"))
+	assert.True(t, s.IsSubtype("IfcWallStandardCase", "IFCPRODUCT"))
+	assert.False(t, s.IsSubtype("IfcOwnerHistory", "IfcRoot"))
+}
+
+func Test_RootTypes(t *testing.T) {
+	f, err := os.Open("testdata/excerpt.exp")
+	assert.NoError(t, err)
+	defer f.Close()
+	s, err := Parse(f)
+	assert.NoError(t, err)
+
+	types, err := RootTypes(s)
+	assert.NoError(t, err)
+	assert.Len(t, types, 9)
+	assert.True(t, types["IFCWALLSTANDARDCASE"])
+	assert.False(t, types["IFCOWNERHISTORY"])
+
+	MergeTypes(types, map[string]bool{"IFCWALL": true, "IFCDOORSTYLE": true})
+	assert.Len(t, types, 10)
+
+	_, err = RootTypes(&Schema{Name: "EMPTY", Entities: map[string]*Entity{}})
+	assert.EqualError(t, err, "schema EMPTY has no IfcRoot entity")
+
+	root := s.Entities["IFCROOT"]
+	root.Attributes = append([]string{"Extra"}, root.Attributes...)
+	_, err = RootTypes(s)
+	assert.ErrorContains(t, err, "is attribute 1, not the first one")
+}
+
+func Test_Parse_with_errors(t *testing.T) {
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"ENTITY IfcRoot; GlobalId : IfcGloballyUniqueId; END_ENTITY;", "SCHEMA declaration missing"},
+		{"SCHEMA IFC4; ENTITY IfcRoot; GlobalId : IfcGloballyUniqueId;", "entity IfcRoot: END_ENTITY missing"},
+		{"SCHEMA IFC4; ENTITY IfcWall SUBTYPE OF (IfcElement); END_ENTITY;", "entity IfcWall: unknown supertype IfcElement"},
+		{"SCHEMA IFC4; ENTITY IfcRoot; GlobalId IfcGloballyUniqueId; END_ENTITY;", `entity IfcRoot: invalid attribute "GlobalId IfcGloballyUniqueId"`},
+		{"SCHEMA IFC4; END_ENTITY;", "END_ENTITY without ENTITY"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.src))
+		assert.EqualError(t, err, tt.want, tt.src)
+	}
+}
@@ -0,0 +1,120 @@
+// Command schemagen generates the IfcRoot subtype tables of the spf package from the EXPRESS schemas of IFC.
+//
+// Usage:
+//
+//	go run ./internal/schemagen [-o file] [-include schema=base] schema=file.exp...
+//
+// For every schema, it lists the entities whose SUBTYPE OF chain ends in IfcRoot, including abstract ones,
+// and checks that their GlobalId is the first attribute, as the spf scanner assumes. With -include,
+// the types of the base schema are added to the schema, e.g. to keep accepting the IFC4 types that IFC4X3 deleted.
+// It is run by go generate in the spf package, see schema.go.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/woweh/ifcguid/spf/internal/express"
+)
+
+// _header is the first line of the generated file.
+const _header = "// Code generated by schemagen; DO NOT EDIT."
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "schemagen:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses the arguments, and writes the generated file to the -o file, or to stdout.
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("schemagen", flag.ContinueOnError)
+	output := fs.String("o", "", "output file")
+	var includes []string
+	fs.Func("include", "add the types of a base schema to a schema, e.g. IFC4X3=IFC4", func(s string) error {
+		includes = append(includes, s)
+		return nil
+	})
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("no schemas")
+	}
+	tables := map[string]map[string]bool{}
+	for _, arg := range fs.Args() {
+		name, file, ok := strings.Cut(arg, "=")
+		if !ok {
+			return fmt.Errorf("invalid schema %q: want name=file.exp", arg)
+		}
+		table, err := readTable(file)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		tables[name] = table
+	}
+	for _, include := range includes {
+		name, base, ok := strings.Cut(include, "=")
+		if !ok || tables[name] == nil || tables[base] == nil {
+			return fmt.Errorf("invalid -include %q: want schema=base", include)
+		}
+		express.MergeTypes(tables[name], tables[base])
+	}
+	src, err := Generate(tables)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(*output, src, 0o644)
+}
+
+// readTable reads an EXPRESS file, and returns its table, see express.RootTypes.
+func readTable(name string) (map[string]bool, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	s, err := express.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	return express.RootTypes(s)
+}
+
+// Generate returns the Go source of the tables, formatted.
+func Generate(tables map[string]map[string]bool) ([]byte, error) {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "%s\n\npackage spf\n\n", _header)
+	b.WriteString("// _schemaRoots holds the IfcRoot subtypes of each schema, including abstract ones, by upper case entity name.\n")
+	b.WriteString("var _schemaRoots = map[string]map[string]bool{\n")
+	for _, name := range sortedKeys(tables) {
+		fmt.Fprintf(&b, "%q: {\n", name)
+		for _, entity := range sortedKeys(tables[name]) {
+			fmt.Fprintf(&b, "%q: true,\n", entity)
+		}
+		b.WriteString("},\n")
+	}
+	b.WriteString("}\n")
+	return format.Source(b.Bytes())
+}
+
+// sortedKeys returns the keys of a map, sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_run(t *testing.T) {
+	var out bytes.Buffer
+	excerpt := "../express/testdata/excerpt.exp"
+	err := run([]string{"-include", "B=A", "A=" + excerpt, "B=" + excerpt}, &out)
+	assert.NoError(t, err)
+	src := out.String()
+	assert.True(t, strings.HasPrefix(src, _header+"\n\npackage spf\n"))
+	assert.Contains(t, src, "\t\"A\": {\n\t\t\"IFCELEMENT\":                true,\n")
+	assert.Equal(t, 2, strings.Count(src, "\"IFCWALLSTANDARDCASE\""))
+	assert.NotContains(t, src, "IFCOWNERHISTORY")
+
+	output := filepath.Join(t.TempDir(), "roots.go")
+	assert.NoError(t, run([]string{"-o", output, "A=" + excerpt}, &out))
+	assert.FileExists(t, output)
+
+	assert.EqualError(t, run(nil, &out), "no schemas")
+	assert.EqualError(t, run([]string{excerpt}, &out), `invalid schema "../express/testdata/excerpt.exp": want name=file.exp`)
+	assert.EqualError(t, run([]string{"-include", "B=C", "A=" + excerpt}, &out), `invalid -include "B=C": want schema=base`)
+	assert.Error(t, run([]string{"A=missing.exp"}, &out))
+}
+
+func Test_Generate(t *testing.T) {
+	src, err := Generate(map[string]map[string]bool{"IFC4": {"IFCWALL": true, "IFCROOT": true}, "IFC2X3": {"IFCROOT": true}})
+	assert.NoError(t, err)
+	assert.Equal(t, _header+`
+
+package spf
+
+// _schemaRoots holds the IfcRoot subtypes of each schema, including abstract ones, by upper case entity name.
+var _schemaRoots = map[string]map[string]bool{
+	"IFC2X3": {
+		"IFCROOT": true,
+	},
+	"IFC4": {
+		"IFCROOT": true,
+		"IFCWALL": true,
+	},
+}
+`, string(src))
+}
@@ -0,0 +1,175 @@
+package spf
+
+import (
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/woweh/ifcguid"
+	"github.com/woweh/ifcguid/ifczip"
+	"github.com/woweh/ifcguid/internal/atomicfile"
+	"github.com/woweh/ifcguid/internal/guidcheck"
+)
+
+// Reasons for replacing a GlobalId, see Replacement.
+const (
+	ReasonInvalid   = guidcheck.ReasonInvalid
+	ReasonDuplicate = guidcheck.ReasonDuplicate
+)
+
+// Replacement records a replaced GlobalId.
+type Replacement struct {
+	// Entity is the entity whose GlobalId was replaced. Its GlobalId field holds the old GlobalId.
+	Entity
+	// New is the new GlobalId.
+	New string
+	// Reason explains why the GlobalId was replaced, e.g. ReasonInvalid or ReasonDuplicate.
+	Reason string
+}
+
+// Strategy returns a new GlobalId for an entity whose GlobalId must be replaced, see Repairer.
+type Strategy func(e Entity) (string, error)
+
+// Random is a Strategy that creates a new random GlobalId with ifcguid.New.
+func Random(Entity) (string, error) {
+	return ifcguid.New()
+}
+
+// Derive returns a Strategy that derives the new GlobalId from the old GlobalId and the entity id,
+// as a name-based UUID (version 5) in the given namespace.
+// Repairing the same file twice gives the same GlobalIds.
+func Derive(namespace uuid.UUID) Strategy {
+	return func(e Entity) (string, error) {
+		return guidcheck.Derive(namespace, e.GlobalId+"#"+strconv.FormatUint(e.Id, 10))
+	}
+}
+
+// Repairer replaces invalid and duplicate GlobalIds in IFC-SPF files.
+type Repairer struct {
+	// Checker finds the GlobalIds to replace. Duplicates suppressed by its rules are kept.
+	Checker
+	// Strategy creates the new GlobalIds. If nil, Random is used.
+	Strategy Strategy
+}
+
+// Repair copies the IFC-SPF file read from r to w, replacing invalid and duplicate GlobalIds,
+// using DefaultRules and Random GlobalIds.
+func Repair(r io.ReadSeeker, w io.Writer) ([]Replacement, error) {
+	rp := Repairer{Checker: Checker{Rules: DefaultRules}}
+	return rp.Repair(r, w)
+}
+
+// RepairFile repairs the IFC-SPF file src with Repair, and writes the result to dst.
+// If src is an .ifczip archive, dst is an .ifczip archive too, see ifczip.RewriteFile.
+// If mapping isn't empty, the replacements are written to a CSV file with that name, see WriteMapping.
+func RepairFile(src, dst, mapping string) ([]Replacement, error) {
+	rp := Repairer{Checker: Checker{Rules: DefaultRules}}
+	return rp.RepairFile(src, dst, mapping)
+}
+
+// Repair copies the IFC-SPF file read from r to w, replacing every invalid GlobalId,
+// and every occurrence but the first of each duplicate GlobalId. All other bytes are copied unchanged.
+//
+// The file is read twice: once to find the problems with the Checker, and once to rewrite it.
+func (rp *Repairer) Repair(r io.ReadSeeker, w io.Writer) ([]Replacement, error) {
+	report, err := rp.Check(r)
+	if err != nil {
+		return nil, err
+	}
+	// Entities are identified by the offset of their GlobalId, because entity ids may be duplicated too.
+	duplicates := make([][]int64, len(report.Duplicates))
+	for i, d := range report.Duplicates {
+		for _, e := range d.Entities {
+			duplicates[i] = append(duplicates[i], e.GlobalIdOffset)
+		}
+	}
+	invalid := make([]int64, len(report.Invalid))
+	for i, inv := range report.Invalid {
+		invalid[i] = inv.GlobalIdOffset
+	}
+	reasons := guidcheck.NewReasons(duplicates, invalid)
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	strategy := rp.Strategy
+	if strategy == nil {
+		strategy = Random
+	}
+	var replacements []Replacement
+	_, err = Rewrite(r, w, func(e Entity) (string, bool, error) {
+		reason, ok := reasons[e.GlobalIdOffset]
+		if !ok {
+			return "", false, nil
+		}
+		newId, err := strategy(e)
+		if err != nil {
+			return "", false, err
+		}
+		replacements = append(replacements, Replacement{Entity: e, New: newId, Reason: reason})
+		return newId, true, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return replacements, nil
+}
+
+// RepairFile repairs the IFC-SPF file src, and writes the result to dst.
+// If src is an .ifczip archive, dst is an .ifczip archive too, see ifczip.RewriteFile.
+// If mapping isn't empty, the replacements are written to a CSV file with that name, see WriteMapping.
+func (rp *Repairer) RepairFile(src, dst, mapping string) ([]Replacement, error) {
+	var replacements []Replacement
+	err := ifczip.RewriteFile(src, dst, ifczip.FormatSpf, func(r io.ReadSeeker, w io.Writer) error {
+		var err error
+		replacements, err = rp.Repair(r, w)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if mapping != "" {
+		if err := writeMappingFile(mapping, replacements); err != nil {
+			return nil, err
+		}
+	}
+	return replacements, nil
+}
+
+// WriteMapping writes replacements as CSV, with the entity id and type, and the old and new GlobalIds.
+// The mapping can be used to migrate BCF topics and databases that reference the old GlobalIds.
+func WriteMapping(w io.Writer, replacements []Replacement) error {
+	mw := NewMappingWriter(w)
+	for _, r := range replacements {
+		_ = mw.Write(r)
+	}
+	return mw.Flush()
+}
+
+// MappingWriter writes replacements as CSV one by one, like WriteMapping,
+// e.g. from a Replacer while a large file is rewritten.
+type MappingWriter struct {
+	mw *guidcheck.MappingWriter
+}
+
+// NewMappingWriter returns a MappingWriter that writes to w.
+func NewMappingWriter(w io.Writer) *MappingWriter {
+	return &MappingWriter{mw: guidcheck.NewMappingWriter(w)}
+}
+
+// Write writes a replacement, preceded by the header line if it is the first one.
+func (mw *MappingWriter) Write(r Replacement) error {
+	return mw.mw.Write("#"+strconv.FormatUint(r.Id, 10), r.Type, r.GlobalId, r.New, r.Reason)
+}
+
+// Flush writes any buffered data, and the header line if nothing was written yet.
+func (mw *MappingWriter) Flush() error {
+	return mw.mw.Flush()
+}
+
+// writeMappingFile writes replacements to a CSV file, see WriteMapping.
+func writeMappingFile(name string, replacements []Replacement) error {
+	return atomicfile.Write(name, func(w io.Writer) error {
+		return WriteMapping(w, replacements)
+	})
+}
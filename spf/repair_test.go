@@ -0,0 +1,133 @@
+package spf
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Repair(t *testing.T) {
+	var out bytes.Buffer
+	replacements, err := Repair(strings.NewReader(_duplicatesFile), &out)
+	assert.NoError(t, err)
+
+	// #11 and #12 duplicate #10, #13 is invalid and #22 is invalid and duplicates #13.
+	// The suppressed duplicates #20 and #21 are kept.
+	var ids []uint64
+	for _, r := range replacements {
+		ids = append(ids, r.Id)
+	}
+	assert.Equal(t, []uint64{11, 12, 13, 22}, ids)
+	assert.Equal(t, ReasonDuplicate, replacements[0].Reason)
+	assert.Equal(t, ReasonInvalid, replacements[2].Reason)
+	assert.Equal(t, ReasonInvalid, replacements[3].Reason)
+
+	// Only the replaced GlobalIds differ.
+	want := _duplicatesFile
+	for _, r := range replacements {
+		want = want[:r.GlobalIdOffset+1] + r.New + want[r.GlobalIdOffset+23:]
+	}
+	assert.Equal(t, want, out.String())
+
+	report, err := Check(strings.NewReader(out.String()))
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+	assert.Len(t, report.Suppressed, 1)
+}
+
+func Test_Repairer_Derive(t *testing.T) {
+	namespace := uuid.MustParse("6ba7b811-9dad-11d1-80b4-00c04fd430c8")
+	rp := Repairer{Strategy: Derive(namespace)}
+
+	var first, second bytes.Buffer
+	replacements, err := rp.Repair(strings.NewReader(_duplicatesFile), &first)
+	assert.NoError(t, err)
+	// Without rules, the material associations are repaired too.
+	assert.Len(t, replacements, 5)
+	_, err = rp.Repair(strings.NewReader(_duplicatesFile), &second)
+	assert.NoError(t, err)
+	assert.Equal(t, first.String(), second.String())
+
+	other, err := Derive(uuid.New())(replacements[0].Entity)
+	assert.NoError(t, err)
+	assert.NotEqual(t, replacements[0].New, other)
+}
+
+func Test_RepairFile(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "model.ifc")
+	dst := filepath.Join(dir, "repaired.ifc")
+	mapping := filepath.Join(dir, "mapping.csv")
+	assert.NoError(t, os.WriteFile(src, []byte(_duplicatesFile), 0o644))
+
+	replacements, err := RepairFile(src, dst, mapping)
+	assert.NoError(t, err)
+	assert.Len(t, replacements, 4)
+
+	data, err := os.ReadFile(mapping)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	assert.Len(t, lines, 5)
+	assert.Equal(t, "Id,Type,Old,New,Reason", lines[0])
+	assert.Equal(t, "#11,IFCWALL,2DWKyvjkf7PffFYiFUDNsy,"+replacements[0].New+",duplicate", lines[1])
+
+	report, err := CheckFile(dst)
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+
+	_, err = RepairFile(filepath.Join(dir, "missing.ifc"), dst, "")
+	assert.Error(t, err)
+
+	// A file can be repaired in place.
+	replacements, err = RepairFile(src, src, "")
+	assert.NoError(t, err)
+	assert.Len(t, replacements, 4)
+	report, err = CheckFile(src)
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+}
+
+func Test_RepairFile_ifczip(t *testing.T) {
+	dir := t.TempDir()
+	src := filepath.Join(dir, "model.ifczip")
+	dst := filepath.Join(dir, "repaired.ifczip")
+	f, err := os.Create(src)
+	assert.NoError(t, err)
+	zw := zip.NewWriter(f)
+	w, err := zw.Create("model/model.ifc")
+	assert.NoError(t, err)
+	_, err = w.Write([]byte(_duplicatesFile))
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	assert.NoError(t, f.Close())
+
+	report, err := CheckFile(src)
+	assert.NoError(t, err)
+	assert.True(t, report.Broken())
+
+	replacements, err := RepairFile(src, dst, "")
+	assert.NoError(t, err)
+	assert.Len(t, replacements, 4)
+
+	report, err = CheckFile(dst)
+	assert.NoError(t, err)
+	assert.False(t, report.Broken())
+	zr, err := zip.OpenReader(dst)
+	assert.NoError(t, err)
+	defer zr.Close()
+	if assert.Len(t, zr.File, 1) {
+		assert.Equal(t, "model/model.ifc", zr.File[0].Name)
+		r, err := zr.File[0].Open()
+		assert.NoError(t, err)
+		data, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Len(t, data, len(_duplicatesFile))
+	}
+}
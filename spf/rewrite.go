@@ -0,0 +1,86 @@
+package spf
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/woweh/ifcguid"
+)
+
+// Replacer returns the new GlobalId of an entity, or false to keep its GlobalId, see Rewrite.
+type Replacer func(e Entity) (newId string, ok bool, err error)
+
+// Rewrite copies the IFC-SPF file read from r to w, and replaces GlobalIds as decided by replace.
+//
+// The Replacer is called for every entity whose first attribute is a string, in file order;
+// use LooksLikeRoot or any other Filter to select entities.
+// New GlobalIds must be valid IFC GUIDs. All other bytes of the file are copied unchanged,
+// and only one record at a time is held in memory.
+// Rewrite returns the number of replaced GlobalIds.
+func Rewrite(r io.Reader, w io.Writer, replace Replacer) (int, error) {
+	s := NewScanner(r)
+	// Every entity is scanned, so that the record buffer is flushed after each record.
+	s.Filter = All
+	var record bytes.Buffer
+	s.lx.echo = &record
+	// start is the offset of the first byte in record.
+	start := int64(0)
+	count := 0
+	for s.Scan() {
+		e := s.Entity()
+		newId, ok := "", false
+		if e.HasGlobalId {
+			var err error
+			if newId, ok, err = replace(e); err != nil {
+				return count, err
+			}
+		}
+		data := record.Bytes()
+		if ok {
+			if err := ifcguid.IsValid(newId); err != nil {
+				return count, fmt.Errorf("#%d: invalid new GlobalId: %w", e.Id, err)
+			}
+			from := e.GlobalIdOffset - start
+			to := from + int64(e.GlobalIdLength)
+			if _, err := w.Write(data[:from]); err != nil {
+				return count, err
+			}
+			if _, err := io.WriteString(w, "'"+newId+"'"); err != nil {
+				return count, err
+			}
+			data = data[to:]
+			count++
+		}
+		if _, err := w.Write(data); err != nil {
+			return count, err
+		}
+		start += int64(record.Len())
+		record.Reset()
+	}
+	if err := s.Err(); err != nil {
+		return count, err
+	}
+	// The rest of the file, after the last entity.
+	_, err := w.Write(record.Bytes())
+	return count, err
+}
+
+// Remap returns a Replacer that replaces the GlobalId of every entity selected by filter with convert(GlobalId),
+// e.g. with the Fork method of an ifcguid.Forker. If filter is nil, IsRoot is used.
+// GlobalIds that convert returns unchanged aren't counted as replaced.
+func Remap(filter Filter, convert func(ifcGuid string) (string, error)) Replacer {
+	if filter == nil {
+		filter = IsRoot
+	}
+	return func(e Entity) (string, bool, error) {
+		if !filter(&e) {
+			return "", false, nil
+		}
+		newId, err := convert(e.GlobalId)
+		if err != nil {
+			return "", false, fmt.Errorf("#%d: %w", e.Id, err)
+		}
+		return newId, newId != e.GlobalId, nil
+	}
+}
@@ -0,0 +1,72 @@
+package spf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Rewrite(t *testing.T) {
+	var out bytes.Buffer
+	count, err := Rewrite(strings.NewReader(_testFile), &out, func(e Entity) (string, bool, error) {
+		if e.Id == 42 {
+			return "3vB2YO$MX4xv5uCqZZG05x", true, nil
+		}
+		return "", false, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, count)
+	want := strings.Replace(_testFile, "#42= IFCWALL('2DWKyvjkf7PffFYiFUDNsy'", "#42= IFCWALL('3vB2YO$MX4xv5uCqZZG05x'", 1)
+	assert.Equal(t, want, out.String())
+
+	// Without replacements, the output is identical to the input, including the trailer.
+	out.Reset()
+	count, err = Rewrite(strings.NewReader(_testFile), &out, func(Entity) (string, bool, error) { return "", false, nil })
+	assert.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Equal(t, _testFile, out.String())
+}
+
+func Test_Rewrite_with_errors(t *testing.T) {
+	_, err := Rewrite(strings.NewReader(_testFile), &bytes.Buffer{}, func(Entity) (string, bool, error) {
+		return "invalid", true, nil
+	})
+	assert.Error(t, err)
+
+	failure := errors.New("failure")
+	_, err = Rewrite(strings.NewReader(_testFile), &bytes.Buffer{}, func(Entity) (string, bool, error) {
+		return "", false, failure
+	})
+	assert.ErrorIs(t, err, failure)
+
+	_, err = Rewrite(strings.NewReader("DATA;\n#1= IFCWALL('x"), &bytes.Buffer{}, func(Entity) (string, bool, error) {
+		return "", false, nil
+	})
+	assert.Error(t, err)
+}
+
+func Test_Remap(t *testing.T) {
+	var out bytes.Buffer
+	count, err := Rewrite(strings.NewReader(_testFile), &out, Remap(nil, func(ifcGuid string) (string, error) {
+		return "3vB2YO$MX4xv5uCqZZG05x", nil
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 4, count)
+	// The comment of the test file contains the GlobalId too.
+	assert.Equal(t, 5, strings.Count(out.String(), "'3vB2YO$MX4xv5uCqZZG05x'"))
+	assert.Contains(t, out.String(), "'0123456789012345678901'", "the property isn't selected by IsRoot")
+
+	_, err = Rewrite(strings.NewReader(_testFile), &bytes.Buffer{}, Remap(All, func(ifcGuid string) (string, error) {
+		return "", errors.New("failure")
+	}))
+	assert.ErrorContains(t, err, "#10: failure")
+
+	count, err = Rewrite(strings.NewReader(_testFile), &bytes.Buffer{}, Remap(nil, func(ifcGuid string) (string, error) {
+		return ifcGuid, nil
+	}))
+	assert.NoError(t, err)
+	assert.Zero(t, count, "unchanged GlobalIds aren't replaced")
+}
@@ -0,0 +1,535 @@
+// Package spf scans IFC files in the STEP physical file format (IFC-SPF, ISO 10303-21) for GlobalIds,
+// without parsing the model.
+//
+// Every instance in the DATA section of an IFC-SPF file is a single record, which may span several lines:
+//
+//	#42= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',#5,'Basic Wall:Generic - 200mm',$,'Basic Wall',#40,#41,'316435');
+//
+// For IfcRoot subtypes, the first attribute is the GlobalId and the third attribute is the Name.
+// The Scanner reads one record at a time and only keeps these two attributes,
+// so files of any size are scanned with constant memory.
+// It knows the IfcRoot subtypes of the schema named in the FILE_SCHEMA of the header, see LookupSchema,
+// and selects their instances; for unknown schemas, it falls back to the LooksLikeRoot heuristic.
+//
+// Check builds on the Scanner to find duplicate and invalid GlobalIds, and Repair replaces them,
+// copying every other byte of the file unchanged. Diff compares two revisions of a model by GlobalId.
+//
+// Usage:
+//
+//	s := spf.NewScanner(file)
+//	for s.Scan() {
+//		e := s.Entity()
+//		fmt.Println(e.Id, e.Type, e.GlobalId, e.Offset)
+//	}
+//	if err := s.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+package spf
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Entity is an instance in the DATA section of an IFC-SPF file.
+type Entity struct {
+	// Id is the instance name, e.g. 42 for #42.
+	Id uint64
+	// Type is the entity type in upper case, as written in the file, e.g. "IFCWALL".
+	// For complex instances, it is the first partial entity type.
+	Type string
+	// Offset is the byte offset of the record, i.e. of its '#'.
+	Offset int64
+	// Line is the 1-based line number of the record.
+	Line int
+	// GlobalId is the decoded first attribute, if it is a string; see HasGlobalId.
+	GlobalId string
+	// HasGlobalId reports whether the first attribute is a string.
+	HasGlobalId bool
+	// GlobalIdOffset is the byte offset of the first attribute, including its quote, or -1 if it isn't a string.
+	GlobalIdOffset int64
+	// GlobalIdLength is the length in bytes of the first attribute as written in the file, including quotes.
+	GlobalIdLength int
+	// Name is the decoded third attribute, if it is a string.
+	Name string
+	// Root reports whether the entity type derives from IfcRoot, according to the schema of the file.
+	// It is false if the schema isn't known, see Scanner.Schema.
+	Root bool
+
+	// schema reports whether the schema of the file is known, i.e. whether Root is meaningful.
+	schema bool
+	// ownerHistory reports whether the second attribute is an instance reference or unset ($),
+	// like the OwnerHistory attribute of IfcRoot.
+	ownerHistory bool
+}
+
+// Filter selects the entities that a Scanner returns.
+type Filter func(e *Entity) bool
+
+// All is a Filter that selects every entity instance.
+func All(*Entity) bool { return true }
+
+// IsRoot is the default Filter of a Scanner.
+// It selects the instances of IfcRoot subtypes, according to the schema of the file, see Scanner.Schema,
+// including those whose first attribute isn't a string, i.e. that are missing their GlobalId.
+// If the schema isn't known, it falls back to LooksLikeRoot.
+func IsRoot(e *Entity) bool {
+	if !e.schema {
+		return LooksLikeRoot(e)
+	}
+	return e.Root
+}
+
+// LooksLikeRoot is a Filter that selects instances whose attributes start like those of IfcRoot, without knowing the schema:
+// the first attribute is a string of 22 characters (IfcGloballyUniqueId),
+// and the second is an instance reference or unset (OwnerHistory).
+// GlobalIds that don't have 22 characters aren't selected; use All to find those.
+// Conversely, other entities that happen to start like this, e.g. a property with a 22 character name
+// and no description, are selected too.
+func LooksLikeRoot(e *Entity) bool {
+	return e.HasGlobalId && len(e.GlobalId) == 22 && e.ownerHistory
+}
+
+// Scanner reads the entity instances of an IFC-SPF file one by one.
+type Scanner struct {
+	// Filter selects the entities that Scan returns. If nil, IsRoot is used.
+	Filter Filter
+
+	lx     *lexer
+	schema *Schema
+	entity Entity
+	err    error
+	// keepTokens, if set, makes readInstance keep every token of the record in tokens, see Diff.
+	keepTokens bool
+	tokens     []token
+}
+
+// NewScanner returns a Scanner that reads from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{lx: newLexer(r)}
+}
+
+// Scan advances the Scanner to the next selected entity, which is then available through Entity.
+// It returns false at the end of the input or after an error, see Err.
+func (s *Scanner) Scan() bool {
+	if s.err != nil {
+		return false
+	}
+	filter := s.Filter
+	if filter == nil {
+		filter = IsRoot
+	}
+	for {
+		ok, err := s.next()
+		if err != nil {
+			s.err = err
+			return false
+		}
+		if !ok {
+			return false
+		}
+		if filter(&s.entity) {
+			return true
+		}
+	}
+}
+
+// Entity returns the entity found by the last call to Scan.
+func (s *Scanner) Entity() Entity {
+	return s.entity
+}
+
+// Err returns the first error encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.err
+}
+
+// Schema returns the schema named in the FILE_SCHEMA of the header, or nil if it isn't known, see LookupSchema.
+// The header is read by the first call to Scan.
+func (s *Scanner) Schema() *Schema {
+	return s.schema
+}
+
+// next reads records until it has read an entity instance, or reached the end of the input.
+func (s *Scanner) next() (bool, error) {
+	for {
+		tok, err := s.lx.next(false)
+		if err != nil {
+			return false, err
+		}
+		switch tok.kind {
+		case tokEOF:
+			return false, nil
+		case tokRef:
+			return true, s.readInstance(tok)
+		case tokWord:
+			if strings.EqualFold(tok.text, "FILE_SCHEMA") {
+				if err := s.readFileSchema(); err != nil {
+					return false, err
+				}
+				continue
+			}
+			if err := s.skipRecord(tok); err != nil {
+				return false, err
+			}
+		default:
+			// Header entities, section keywords, and anything else that isn't an instance.
+			if err := s.skipRecord(tok); err != nil {
+				return false, err
+			}
+		}
+	}
+}
+
+// readFileSchema reads the rest of the FILE_SCHEMA record of the header, e.g. FILE_SCHEMA(('IFC4'));
+// If it names several schemas, the first one is used.
+func (s *Scanner) readFileSchema() error {
+	found := false
+	for {
+		tok, err := s.lx.next(!found)
+		if err != nil {
+			return err
+		}
+		switch tok.kind {
+		case tokEOF, tokSemicolon:
+			return nil
+		case tokString:
+			if found {
+				break
+			}
+			found = true
+			name, err := decodeString(tok.text)
+			if err != nil {
+				return s.lx.errorf(tok, "%v", err)
+			}
+			s.schema = LookupSchema(name)
+		}
+	}
+}
+
+// readInstance reads the rest of an entity instance record that starts with ref.
+func (s *Scanner) readInstance(ref token) error {
+	id, err := strconv.ParseUint(ref.text[1:], 10, 64)
+	if err != nil {
+		return s.lx.errorf(ref, "invalid instance name %q", ref.text)
+	}
+	s.entity = Entity{Id: id, Offset: ref.offset, Line: ref.line, GlobalIdOffset: -1, schema: s.schema != nil}
+	if s.keepTokens {
+		s.tokens = s.tokens[:0]
+		s.lx.record = &s.tokens
+		defer func() { s.lx.record = nil }()
+	}
+
+	tok, err := s.lx.next(false)
+	if err != nil {
+		return err
+	}
+	if tok.kind != tokEquals {
+		return s.lx.errorf(tok, "expected '=' after %s", ref.text)
+	}
+	tok, err = s.lx.next(false)
+	if err != nil {
+		return err
+	}
+	switch tok.kind {
+	case tokWord:
+		s.entity.Type = strings.ToUpper(tok.text)
+		s.entity.Root = s.schema != nil && s.schema.IsRoot(s.entity.Type)
+	case tokOpen:
+		// Complex instance: (IFCA(...) IFCB(...)). Only the type of the first part is kept.
+		tok, err = s.lx.next(false)
+		if err != nil {
+			return err
+		}
+		if tok.kind == tokWord {
+			s.entity.Type = strings.ToUpper(tok.text)
+		}
+		return s.skipRecord(tok)
+	default:
+		return s.lx.errorf(tok, "expected entity type after %s=", ref.text)
+	}
+
+	tok, err = s.lx.next(false)
+	if err != nil {
+		return err
+	}
+	if tok.kind != tokOpen {
+		return s.lx.errorf(tok, "expected '(' after %s", s.entity.Type)
+	}
+	attr, depth := 0, 1
+	for depth > 0 {
+		// Only the first and the third attribute are decoded, as long as they are plain strings.
+		keep := depth == 1 && (attr == 0 || attr == 2)
+		tok, err = s.lx.next(keep)
+		if err != nil {
+			return err
+		}
+		switch tok.kind {
+		case tokEOF:
+			return s.lx.errorf(tok, "unexpected end of file in #%d", id)
+		case tokSemicolon:
+			return s.lx.errorf(tok, "unexpected ';' in #%d", id)
+		case tokOpen:
+			depth++
+		case tokClose:
+			depth--
+		case tokComma:
+			if depth == 1 {
+				attr++
+			}
+		case tokString:
+			if !keep {
+				break
+			}
+			value, err := decodeString(tok.text)
+			if err != nil {
+				return s.lx.errorf(tok, "%v", err)
+			}
+			if attr == 0 {
+				s.entity.GlobalId = value
+				s.entity.HasGlobalId = true
+				s.entity.GlobalIdOffset = tok.offset
+				s.entity.GlobalIdLength = tok.length
+			} else {
+				s.entity.Name = value
+			}
+		case tokRef, tokDollar:
+			if depth == 1 && attr == 1 {
+				s.entity.ownerHistory = true
+			}
+		}
+	}
+	tok, err = s.lx.next(false)
+	if err != nil {
+		return err
+	}
+	if tok.kind != tokSemicolon {
+		return s.lx.errorf(tok, "expected ';' after #%d", id)
+	}
+	return nil
+}
+
+// skipRecord skips the tokens up to and including the ';' that ends the record, starting with tok.
+func (s *Scanner) skipRecord(tok token) error {
+	var err error
+	for tok.kind != tokSemicolon {
+		if tok.kind == tokEOF {
+			// Trailing garbage without ';' is tolerated at the end of the file.
+			return nil
+		}
+		if tok, err = s.lx.next(false); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tokenKind is the kind of token returned by the lexer.
+type tokenKind int
+
+const (
+	tokEOF       tokenKind = iota
+	tokRef                 // #123
+	tokEquals              // =
+	tokOpen                // (
+	tokClose               // )
+	tokComma               // ,
+	tokSemicolon           // ;
+	tokString              // '...'
+	tokDollar              // $
+	tokWord                // keywords, numbers, enumerations, binaries and '*'
+)
+
+// token is a lexical token of an IFC-SPF file.
+type token struct {
+	kind tokenKind
+	// text is the token as written in the file. For strings, it is only set if requested,
+	// and it is the content between the quotes, still encoded.
+	text string
+	// offset is the byte offset of the token.
+	offset int64
+	// length is the length of the token in bytes.
+	length int
+	// line is the 1-based line number of the token.
+	line int
+}
+
+// lexer splits an IFC-SPF file into tokens, skipping white space and comments.
+type lexer struct {
+	r      *bufio.Reader
+	offset int64
+	line   int
+	buf    strings.Builder
+	// echo, if set, receives every byte that is read, see Rewrite.
+	echo *bytes.Buffer
+	// record, if set, receives every token, with the text of strings, see Scanner.keepTokens.
+	record *[]token
+}
+
+func newLexer(r io.Reader) *lexer {
+	return &lexer{r: bufio.NewReaderSize(r, 64*1024), line: 1}
+}
+
+// readByte reads the next byte and keeps track of the offset and line.
+func (l *lexer) readByte() (byte, error) {
+	c, err := l.r.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+	l.offset++
+	if l.echo != nil {
+		l.echo.WriteByte(c)
+	}
+	if c == '\n' {
+		l.line++
+	}
+	return c, nil
+}
+
+// peekByte returns the next byte without consuming it, or 0 at the end of the input.
+func (l *lexer) peekByte() byte {
+	b, err := l.r.Peek(1)
+	if err != nil {
+		return 0
+	}
+	return b[0]
+}
+
+// errorf returns an error that includes the position of tok.
+func (l *lexer) errorf(tok token, format string, args ...any) error {
+	return fmt.Errorf("line %d, offset %d: %s", tok.line, tok.offset, fmt.Sprintf(format, args...))
+}
+
+// next returns the next token. If keep is false, the text of string tokens isn't kept.
+func (l *lexer) next(keep bool) (token, error) {
+	if l.record == nil {
+		return l.scan(keep)
+	}
+	tok, err := l.scan(true)
+	if err == nil {
+		*l.record = append(*l.record, tok)
+	}
+	return tok, err
+}
+
+// scan returns the next token, see next.
+func (l *lexer) scan(keep bool) (token, error) {
+	for {
+		start, line := l.offset, l.line
+		c, err := l.readByte()
+		if err == io.EOF {
+			return token{kind: tokEOF, offset: start, line: line}, nil
+		}
+		if err != nil {
+			return token{}, err
+		}
+		tok := token{offset: start, line: line, length: 1, text: string(c)}
+		switch c {
+		case ' ', '\t', '\r', '\n', '\f', '\v':
+			continue
+		case '/':
+			if l.peekByte() == '*' {
+				if err := l.skipComment(start, line); err != nil {
+					return token{}, err
+				}
+				continue
+			}
+			tok.kind = tokWord
+		case '=':
+			tok.kind = tokEquals
+		case '(':
+			tok.kind = tokOpen
+		case ')':
+			tok.kind = tokClose
+		case ',':
+			tok.kind = tokComma
+		case ';':
+			tok.kind = tokSemicolon
+		case '$':
+			tok.kind = tokDollar
+		case '\'':
+			return l.readString(tok, keep)
+		case '#':
+			tok.kind = tokRef
+			l.readWord(&tok)
+		default:
+			tok.kind = tokWord
+			l.readWord(&tok)
+		}
+		return tok, nil
+	}
+}
+
+// skipComment skips a comment, after its '/'.
+func (l *lexer) skipComment(start int64, line int) error {
+	if _, err := l.readByte(); err != nil { // '*'
+		return err
+	}
+	star := false
+	for {
+		c, err := l.readByte()
+		if err == io.EOF {
+			return l.errorf(token{offset: start, line: line}, "unterminated comment")
+		}
+		if err != nil {
+			return err
+		}
+		if star && c == '/' {
+			return nil
+		}
+		star = c == '*'
+	}
+}
+
+// readString reads a string token, after its opening quote. Quotes are escaped by doubling them.
+func (l *lexer) readString(tok token, keep bool) (token, error) {
+	tok.kind = tokString
+	l.buf.Reset()
+	for {
+		c, err := l.readByte()
+		if err == io.EOF {
+			return token{}, l.errorf(tok, "unterminated string")
+		}
+		if err != nil {
+			return token{}, err
+		}
+		if c == '\'' {
+			if l.peekByte() != '\'' {
+				break
+			}
+			if _, err := l.readByte(); err != nil {
+				return token{}, err
+			}
+		}
+		if keep {
+			l.buf.WriteByte(c)
+		}
+	}
+	tok.length = int(l.offset - tok.offset)
+	tok.text = ""
+	if keep {
+		tok.text = l.buf.String()
+	}
+	return tok, nil
+}
+
+// readWord reads the rest of a word or instance reference, up to the next delimiter.
+func (l *lexer) readWord(tok *token) {
+	l.buf.Reset()
+	l.buf.WriteString(tok.text)
+	for {
+		c := l.peekByte()
+		switch c {
+		case 0, ' ', '\t', '\r', '\n', '\f', '\v', '=', '(', ')', ',', ';', '$', '\'', '#', '/':
+			tok.text = l.buf.String()
+			tok.length = len(tok.text)
+			return
+		}
+		_, _ = l.readByte()
+		l.buf.WriteByte(c)
+	}
+}
@@ -0,0 +1,162 @@
+package spf
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const _testFile = `ISO-10303-21;
+HEADER;
+FILE_DESCRIPTION(('ViewDefinition [CoordinationView]'),'2;1');
+FILE_NAME('test.ifc','2024-05-01T12:00:00',(''),(''),'The EXPRESS Data Manager','Exporter 1.0','');
+FILE_SCHEMA(('IFC2X3'));
+ENDSEC;
+
+DATA;
+#1= IFCPERSON($,$,'',$,$,$,$,$);
+#5= IFCOWNERHISTORY(#1,#2,$,.NOCHANGE.,$,$,$,0);
+#10= IFCPROJECT('0YvctVUKr0kugbFTf53O9L',#5,'Project $ ''1''',$,$,$,$,(#20),#30);
+/* a comment with 'quotes' and #99= IFCWALL('3vB2YO$MX4xv5uCqZZG05x',#5,$,$); */
+#42= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',
+  #5,
+  'Basic Wall:Generic - 200mm (\X2\00C4\X0\)',$,'Basic Wall',#40,#41,'316435');
+#43=IFCWALLSTANDARDCASE('2DWKyvjkf7PffFYiFUDNsz',$,$,$,$,$,$,$);
+#50= IFCPROPERTYSINGLEVALUE('0123456789012345678901','Description',IFCLABEL('x'),$);
+#51= IFCMATERIAL('Concrete');
+#60= IFCSITE('short',#5,'Site',$,$,$,$,$,.ELEMENT.,$,$,$,$,$);
+#70=(IFCLENGTHMEASURE(1.) IFCREAL(2.));
+#80= IFCCARTESIANPOINT((0.,0.,1.E-3));
+ENDSEC;
+END-ISO-10303-21;
+`
+
+func Test_Scanner(t *testing.T) {
+	s := NewScanner(strings.NewReader(_testFile))
+	var got []Entity
+	for s.Scan() {
+		got = append(got, s.Entity())
+	}
+	assert.NoError(t, s.Err())
+
+	assert.Equal(t, "IFC2X3", s.Schema().Name)
+	if assert.Len(t, got, 4) {
+		assert.Equal(t, uint64(10), got[0].Id)
+		assert.True(t, got[0].Root)
+		assert.Equal(t, "IFCPROJECT", got[0].Type)
+		assert.Equal(t, "0YvctVUKr0kugbFTf53O9L", got[0].GlobalId)
+		assert.Equal(t, "Project $ '1'", got[0].Name)
+
+		wall := got[1]
+		assert.Equal(t, uint64(42), wall.Id)
+		assert.Equal(t, "IFCWALL", wall.Type)
+		assert.Equal(t, "2DWKyvjkf7PffFYiFUDNsy", wall.GlobalId)
+		assert.Equal(t, "Basic Wall:Generic - 200mm (Ä)", wall.Name)
+		assert.Equal(t, 13, wall.Line)
+		assert.Equal(t, "#42=", _testFile[wall.Offset:wall.Offset+4])
+		assert.Equal(t, "'2DWKyvjkf7PffFYiFUDNsy'", _testFile[wall.GlobalIdOffset:wall.GlobalIdOffset+int64(wall.GlobalIdLength)])
+
+		assert.Equal(t, uint64(43), got[2].Id)
+		assert.Equal(t, 16, got[2].Line)
+		assert.Empty(t, got[2].Name)
+
+		// The schema knows that IfcSite derives from IfcRoot, even though its GlobalId is invalid.
+		assert.Equal(t, uint64(60), got[3].Id)
+		assert.Equal(t, "short", got[3].GlobalId)
+	}
+
+	// Without a known schema, the heuristic doesn't select the site.
+	s = NewScanner(strings.NewReader(strings.Replace(_testFile, "'IFC2X3'", "'IFC9'", 1)))
+	var ids []uint64
+	for s.Scan() {
+		assert.False(t, s.Entity().Root)
+		ids = append(ids, s.Entity().Id)
+	}
+	assert.NoError(t, s.Err())
+	assert.Nil(t, s.Schema())
+	assert.Equal(t, []uint64{10, 42, 43}, ids)
+}
+
+func Test_Scanner_All(t *testing.T) {
+	s := NewScanner(strings.NewReader(_testFile))
+	s.Filter = All
+	var ids []uint64
+	var types []string
+	for s.Scan() {
+		ids = append(ids, s.Entity().Id)
+		types = append(types, s.Entity().Type)
+	}
+	assert.NoError(t, s.Err())
+	assert.Equal(t, []uint64{1, 5, 10, 42, 43, 50, 51, 60, 70, 80}, ids)
+	assert.Equal(t, "IFCLENGTHMEASURE", types[8])
+
+	s = NewScanner(strings.NewReader(_testFile))
+	s.Filter = All
+	for s.Scan() {
+		e := s.Entity()
+		switch e.Id {
+		case 1, 5, 70, 80:
+			assert.False(t, e.HasGlobalId, e.Id)
+			assert.Equal(t, int64(-1), e.GlobalIdOffset, e.Id)
+		case 60:
+			assert.True(t, e.HasGlobalId)
+			assert.Equal(t, "short", e.GlobalId)
+			assert.False(t, LooksLikeRoot(&e))
+		case 50:
+			assert.False(t, LooksLikeRoot(&e), "the second attribute of IfcPropertySingleValue is a string")
+		}
+	}
+}
+
+func Test_Scanner_with_invalid_data(t *testing.T) {
+	for _, data := range []string{
+		"DATA;\n#1= IFCWALL('unterminated,$);\nENDSEC;",
+		"DATA;\n#1 IFCWALL('2DWKyvjkf7PffFYiFUDNsy',$);",
+		"DATA;\n#1= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',$;",
+		"DATA;\n#1= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',$",
+		"DATA;\n#x= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',$);",
+		"DATA;\n/* unterminated comment",
+	} {
+		s := NewScanner(strings.NewReader(data))
+		for s.Scan() {
+		}
+		assert.Error(t, s.Err(), data)
+	}
+}
+
+// repeatReader repeats a block of records n times, to scan a large file without holding it in memory.
+type repeatReader struct {
+	block string
+	n     int
+	pos   int
+}
+
+func (r *repeatReader) Read(p []byte) (int, error) {
+	if r.n == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.block[r.pos:])
+	r.pos += n
+	if r.pos == len(r.block) {
+		r.pos = 0
+		r.n--
+	}
+	return n, nil
+}
+
+func Test_Scanner_large_input(t *testing.T) {
+	r := &repeatReader{block: "#1= IFCWALL('2DWKyvjkf7PffFYiFUDNsy',#5,'Wall',$);\n", n: 200_000}
+	s := NewScanner(r)
+	count := 0
+	var last Entity
+	for s.Scan() {
+		count++
+		last = s.Entity()
+	}
+	assert.NoError(t, s.Err())
+	assert.Equal(t, 200_000, count)
+	assert.Equal(t, 200_000, last.Line)
+	assert.Equal(t, int64(51*199_999), last.Offset)
+}
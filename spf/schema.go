@@ -0,0 +1,49 @@
+package spf
+
+import (
+	"strings"
+)
+
+//go:generate go run ./internal/schemagen -o schema_roots.go -include IFC4X3=IFC4 IFC2X3=schemas/IFC2X3_TC1.exp IFC4=schemas/IFC4.exp IFC4X3=schemas/IFC4X3_ADD2.exp
+
+// Schema lists the entity types of an IFC schema that derive from IfcRoot, see LookupSchema.
+//
+// The lists are generated by schemagen, see schemas/README.md.
+// The GlobalId is the first attribute of IfcRoot, and inherited attributes come first in IFC-SPF,
+// so the GlobalId is the first attribute of the instances of all these types; schemagen checks this.
+type Schema struct {
+	// Name is the name of the schema, e.g. "IFC4".
+	Name string
+
+	roots map[string]bool
+}
+
+// IsRoot reports whether the entity type derives from IfcRoot. Types are compared case-insensitively.
+func (s *Schema) IsRoot(entityType string) bool {
+	return s.roots[strings.ToUpper(entityType)]
+}
+
+// _schemas are the known schemas, by name.
+var _schemas = map[string]*Schema{
+	"IFC2X3": newSchema("IFC2X3"),
+	"IFC4":   newSchema("IFC4"),
+	// IFC4X3 includes the IFC4 types it deleted, e.g. IfcWallStandardCase, which exporters still write.
+	"IFC4X3": newSchema("IFC4X3"),
+}
+
+// LookupSchema returns the schema for a schema identifier in the FILE_SCHEMA of an IFC-SPF file header,
+// e.g. "IFC4" or "IFC4X3_ADD2", or nil if the schema isn't known.
+// Known are IFC2X3, IFC4 and IFC4X3, including their addenda and corrigenda.
+func LookupSchema(fileSchema string) *Schema {
+	name := strings.ToUpper(strings.TrimSpace(fileSchema))
+	if i := strings.IndexByte(name, '_'); i >= 0 {
+		// Addenda and corrigenda don't change the entity hierarchy, e.g. IFC4_ADD2_TC1 or IFC4X3_ADD2.
+		name = name[:i]
+	}
+	return _schemas[name]
+}
+
+// newSchema returns the schema with the given name, with its table from _schemaRoots.
+func newSchema(name string) *Schema {
+	return &Schema{Name: name, roots: _schemaRoots[name]}
+}
@@ -0,0 +1,114 @@
+package spf
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+
+	"github.com/woweh/ifcguid/spf/internal/express"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_LookupSchema(t *testing.T) {
+	tests := []struct {
+		fileSchema string
+		want       string
+	}{
+		{"IFC2X3", "IFC2X3"},
+		{"IFC4", "IFC4"},
+		{"ifc4", "IFC4"},
+		{"IFC4_ADD2_TC1", "IFC4"},
+		{"IFC4X3", "IFC4X3"},
+		{"IFC4X3_ADD2", "IFC4X3"},
+		{" IFC4X3_TC1 ", "IFC4X3"},
+		{"IFC4X1", ""},
+		{"IFC5", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fileSchema, func(t *testing.T) {
+			s := LookupSchema(tt.fileSchema)
+			if tt.want == "" {
+				assert.Nil(t, s)
+				return
+			}
+			if assert.NotNil(t, s) {
+				assert.Equal(t, tt.want, s.Name)
+			}
+		})
+	}
+}
+
+func Test_Schema_IsRoot(t *testing.T) {
+	tests := []struct {
+		entityType string
+		ifc2x3     bool
+		ifc4       bool
+		ifc4x3     bool
+	}{
+		{"IFCROOT", true, true, true},
+		{"IFCWALL", true, true, true},
+		{"IfcWallStandardCase", true, true, true},
+		{"IFCPROJECT", true, true, true},
+		{"IFCRELASSOCIATESMATERIAL", true, true, true},
+		{"IFCPROPERTYSET", true, true, true},
+		{"IFCELEMENTQUANTITY", true, true, true},
+		{"IFCENERGYPROPERTIES", true, false, false},
+		{"IFCELECTRICALELEMENT", true, false, false},
+		{"IFCRELDECLARES", false, true, true},
+		{"IFCPROJECTLIBRARY", false, true, true},
+		{"IFCSPATIALZONE", false, true, true},
+		{"IFCALIGNMENT", false, false, true},
+		{"IFCBRIDGE", false, false, true},
+		{"IFCBUILTELEMENT", false, false, true},
+		{"IFCGEOTECHNICALSTRATUM", false, false, true},
+		{"IFCSOLIDSTRATUM", false, false, true},
+		{"IFCVOIDSTRATUM", false, false, true},
+		{"IfcWaterStratum", false, false, true},
+		{"IFCOWNERHISTORY", false, false, false},
+		{"IFCPROPERTYSINGLEVALUE", false, false, false},
+		{"IFCMATERIAL", false, false, false},
+		{"IFCCARTESIANPOINT", false, false, false},
+		{"IFCSHAPEREPRESENTATION", false, false, false},
+	}
+	ifc2x3, ifc4, ifc4x3 := LookupSchema("IFC2X3"), LookupSchema("IFC4"), LookupSchema("IFC4X3")
+	for _, tt := range tests {
+		t.Run(tt.entityType, func(t *testing.T) {
+			assert.Equal(t, tt.ifc2x3, ifc2x3.IsRoot(tt.entityType), "IFC2X3")
+			assert.Equal(t, tt.ifc4, ifc4.IsRoot(tt.entityType), "IFC4")
+			assert.Equal(t, tt.ifc4x3, ifc4x3.IsRoot(tt.entityType), "IFC4X3")
+		})
+	}
+}
+
+// Test_schemaRoots checks the generated tables against the EXPRESS files in schemas, if they are there.
+func Test_schemaRoots(t *testing.T) {
+	files := map[string]string{
+		"IFC2X3": "schemas/IFC2X3_TC1.exp",
+		"IFC4":   "schemas/IFC4.exp",
+		"IFC4X3": "schemas/IFC4X3_ADD2.exp",
+	}
+	tables := map[string]map[string]bool{}
+	for name, file := range files {
+		f, err := os.Open(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			t.Skipf("%s not found, see schemas/README.md", file)
+		}
+		if !assert.NoError(t, err) {
+			return
+		}
+		s, err := express.Parse(f)
+		f.Close()
+		if !assert.NoError(t, err, file) {
+			return
+		}
+		tables[name], err = express.RootTypes(s)
+		if !assert.NoError(t, err, file) {
+			return
+		}
+	}
+	express.MergeTypes(tables["IFC4X3"], tables["IFC4"])
+	assert.Equal(t, tables, _schemaRoots, "schema_roots.go is out of date, run go generate")
+}
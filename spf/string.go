@@ -0,0 +1,79 @@
+package spf
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// decodeString decodes the content of a STEP string, without its quotes and with doubled quotes already merged.
+//
+// The following control directives of ISO 10303-21 are decoded:
+//   - \\ for a backslash
+//   - \S\c for the ISO 8859 character c+128
+//   - \X\hh for the ISO 8859-1 character hh
+//   - \X2\hhhh...\X0\ for UCS-2 (UTF-16) characters
+//   - \X4\hhhhhhhh...\X0\ for UCS-4 characters
+//
+// Code page directives (\P?\) are skipped, and \S\ always uses ISO 8859-1.
+// Other bytes, including UTF-8 and unescaped backslashes written by some exporters, are kept as they are.
+func decodeString(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			i++
+			continue
+		}
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, `\\`):
+			b.WriteByte('\\')
+			i += 2
+		case strings.HasPrefix(rest, `\S\`) && len(rest) > 3:
+			b.WriteRune(rune(rest[3]) + 128)
+			i += 4
+		case strings.HasPrefix(rest, `\P`) && len(rest) >= 4 && rest[3] == '\\':
+			i += 4
+		case strings.HasPrefix(rest, `\X\`) && len(rest) >= 5:
+			v, err := strconv.ParseUint(rest[3:5], 16, 8)
+			if err != nil {
+				return "", fmt.Errorf("invalid \\X\\ directive in string %q", s)
+			}
+			b.WriteRune(rune(v))
+			i += 5
+		case strings.HasPrefix(rest, `\X2\`), strings.HasPrefix(rest, `\X4\`):
+			digits := 4
+			if rest[2] == '4' {
+				digits = 8
+			}
+			end := strings.Index(rest[4:], `\X0\`)
+			if end < 0 || end%digits != 0 {
+				return "", fmt.Errorf("invalid %s directive in string %q", rest[:4], s)
+			}
+			var units []uint16
+			for j := 4; j < 4+end; j += digits {
+				v, err := strconv.ParseUint(rest[j:j+digits], 16, 32)
+				if err != nil {
+					return "", fmt.Errorf("invalid %s directive in string %q", rest[:4], s)
+				}
+				if digits == 8 {
+					b.WriteRune(rune(v))
+				} else {
+					units = append(units, uint16(v))
+				}
+			}
+			b.WriteString(string(utf16.Decode(units)))
+			i += 4 + end + 4
+		default:
+			// Unknown directives, like unescaped backslashes in file paths, are kept as they are.
+			b.WriteByte('\\')
+			i++
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,38 @@
+package spf
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_decodeString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"2DWKyvjkf7PffFYiFUDNsy", "2DWKyvjkf7PffFYiFUDNsy"},
+		{"it's", "it's"},
+		{`C:\\Temp`, `C:\Temp`},
+		{`C:\Temp`, `C:\Temp`},
+		{`Stra\S\_e`, "Straße"},
+		{`\PA\Stra\S\_e`, "Straße"},
+		{`Stra\X\DFe`, "Straße"},
+		{`\X2\00C400D6\X0\ and \X2\D83DDE00\X0\`, "ÄÖ and 😀"},
+		{`\X4\0001F600\X0\`, "😀"},
+		{"Grüße", "Grüße"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := decodeString(tt.in)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, s := range []string{`\X\G0`, `\X2\00C4`, `\X2\00C\X0\`, `\X4\0001F60Z\X0\`} {
+		_, err := decodeString(s)
+		assert.Error(t, err, s)
+	}
+}
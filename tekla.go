@@ -0,0 +1,74 @@
+package ifcguid
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// SchemeNameTekla is the name of the built-in Tekla Structures GUID scheme.
+const SchemeNameTekla = "tekla"
+
+// SchemeTekla handles Tekla Structures GUIDs, see FromTeklaGuid.
+var SchemeTekla IdScheme = teklaScheme{}
+
+// _teklaGuid matches a Tekla GUID: "ID" followed by a GUID in the 8-4-4-4-12 form.
+var _teklaGuid = regexp.MustCompile(`^ID[0-9A-Fa-f]{8}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{4}-[0-9A-Fa-f]{12}$`)
+
+func init() {
+	mustRegisterScheme(SchemeTekla)
+}
+
+// IsValidTeklaGuid checks if a string is a Tekla Structures GUID.
+func IsValidTeklaGuid(teklaGuid string) bool {
+	/*
+		Tekla writes GUIDs in reports and IFC property sets as "ID" followed by the GUID in upper case,
+		e.g. ID52A8B2C6-0000-0D51-3134-353338303231. => 2 + 36 = 38 chars
+		Tekla GUIDs don't necessarily follow RFC 4122, so the version and variant aren't checked.
+	*/
+	return len(teklaGuid) == 38 && _teklaGuid.MatchString(teklaGuid)
+}
+
+// FromTeklaGuid converts a Tekla Structures GUID (e.g. "ID52A8B2C6-0000-0D51-3134-353338303231") to an IFC GUID.
+// The result matches the GlobalId written by Tekla's IFC export.
+func FromTeklaGuid(teklaGuid string) (string, error) {
+	u, err := teklaGuidToUuid(teklaGuid)
+	if err != nil {
+		return "", err
+	}
+	return FromUuid(u)
+}
+
+// ToTeklaGuid converts an IFC GUID to a Tekla Structures GUID.
+func ToTeklaGuid(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	return uuidToTeklaGuid(u), nil
+}
+
+// teklaGuidToUuid converts a Tekla GUID to a UUID.
+func teklaGuidToUuid(teklaGuid string) (uuid.UUID, error) {
+	if !IsValidTeklaGuid(teklaGuid) {
+		return uuid.Nil, fmt.Errorf("the given string isn't a Tekla GUID: %v", teklaGuid)
+	}
+	return uuid.Parse(teklaGuid[2:])
+}
+
+// uuidToTeklaGuid converts a UUID to a Tekla GUID.
+func uuidToTeklaGuid(u uuid.UUID) string {
+	return "ID" + strings.ToUpper(u.String())
+}
+
+// teklaScheme implements IdScheme for Tekla GUIDs.
+type teklaScheme struct{}
+
+func (teklaScheme) Name() string                           { return SchemeNameTekla }
+func (teklaScheme) Detect(value string) bool               { return IsValidTeklaGuid(value) }
+func (teklaScheme) ToUuid(value string) (uuid.UUID, error) { return teklaGuidToUuid(value) }
+func (teklaScheme) FromUuid(u uuid.UUID) (string, error)   { return uuidToTeklaGuid(u), nil }
+func (teklaScheme) Reversible() bool                       { return true }
+func (teklaScheme) Confidence(string) float64              { return 1 }
@@ -0,0 +1,77 @@
+package ifcguid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_TeklaGuid_conversions(t *testing.T) {
+	tests := []struct {
+		name      string
+		teklaGuid string
+		ifcGuid   string
+		wantError bool
+	}{
+		{
+			name:      "Report GUID",
+			teklaGuid: "ID52A8B2C6-0000-0D51-3134-353338303231",
+			ifcGuid:   "1IgBB6000DKJ4qDJCuC38n",
+		},
+		{
+			name:      "Random GUID",
+			teklaGuid: "ID3085A8E4-61FD-4776-9FF1-1B24A646CA4F",
+			ifcGuid:   "0mXQZaOVr7Tf$n6oIcHifF",
+		},
+		{
+			name:      "Missing prefix",
+			teklaGuid: "52A8B2C6-0000-0D51-3134-353338303231",
+			wantError: true,
+		},
+		{
+			name:      "Lower case prefix",
+			teklaGuid: "id52A8B2C6-0000-0D51-3134-353338303231",
+			wantError: true,
+		},
+		{
+			name:      "Braced GUID",
+			teklaGuid: "ID{52A8B2C6-0000-0D51-3134-353338303231}",
+			wantError: true,
+		},
+		{
+			name:      "Invalid characters",
+			teklaGuid: "ID52A8B2C6-0000-0D51-3134-35333830323G",
+			wantError: true,
+		},
+		{
+			name:      "Empty string",
+			teklaGuid: "",
+			wantError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIfcGuid, err := FromTeklaGuid(tt.teklaGuid)
+			if tt.wantError {
+				assert.Error(t, err)
+				assert.False(t, IsValidTeklaGuid(tt.teklaGuid))
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, IsValidTeklaGuid(tt.teklaGuid))
+			assert.Equal(t, tt.ifcGuid, gotIfcGuid)
+
+			gotTeklaGuid, err := ToTeklaGuid(gotIfcGuid)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.teklaGuid, gotTeklaGuid)
+
+			normalized, err := Normalize(tt.teklaGuid)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.ifcGuid, normalized)
+		})
+	}
+
+	_, err := ToTeklaGuid("invalid")
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package ifcguid
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeBased describes a GlobalId created from a time-based UUID (version 1), see InspectTimeBased.
+//
+// Time-based UUIDs embed the creation time and the node id of the computer that created them,
+// which is usually the MAC address of a network card.
+type TimeBased struct {
+	// GlobalId is the inspected IFC GUID.
+	GlobalId string
+	// Time is the creation time, with a resolution of 100 ns.
+	Time time.Time
+	// ClockSequence is the 14-bit clock sequence, which keeps UUIDs unique when the clock is set back.
+	ClockSequence int
+	// Node is the 48-bit node id.
+	Node net.HardwareAddr
+	// Random reports whether the node id is random rather than a MAC address, i.e. whether its multicast bit is set.
+	Random bool
+}
+
+// InspectTimeBased reports the creation time and node id of a GlobalId created from a time-based UUID (version 1).
+// It returns false if the GlobalId isn't time-based.
+func InspectTimeBased(ifcGuid string) (TimeBased, bool, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return TimeBased{}, false, err
+	}
+	if !isTimeBased(u) {
+		return TimeBased{}, false, nil
+	}
+	node := net.HardwareAddr(u.NodeID())
+	return TimeBased{
+		GlobalId:      ifcGuid,
+		Time:          uuidTime(u),
+		ClockSequence: u.ClockSequence(),
+		Node:          node,
+		Random:        node[0]&1 == 1,
+	}, true, nil
+}
+
+// Anonymizer removes the node id from time-based GlobalIds, and optionally coarsens their creation time,
+// so that models can be shared without revealing which computers created them, and when.
+//
+// All GlobalIds with the same node id get the same replacement node id, derived from the node id with HMAC-SHA256
+// and a key, so GlobalIds from different computers stay distinct. If coarsening the time makes a GlobalId equal
+// to one anonymized before, the clock sequence is incremented, like a UUID generator does when its clock is set back.
+// An Anonymizer remembers every GlobalId it has anonymized, so that the same GlobalId is anonymized consistently,
+// e.g. across a whole IFC file. It isn't safe for concurrent use.
+type Anonymizer struct {
+	key       []byte
+	precision time.Duration
+	mapping   map[uuid.UUID]uuid.UUID
+	used      map[uuid.UUID]bool
+}
+
+// NewAnonymizer returns an Anonymizer that derives replacement node ids with the given key,
+// and rounds creation times down to multiples of precision.
+// If key is empty, a random key is used, and the results can't be reproduced.
+// If precision is zero, creation times are kept.
+func NewAnonymizer(key []byte, precision time.Duration) (*Anonymizer, error) {
+	if precision < 0 {
+		return nil, fmt.Errorf("the precision must not be negative: %v", precision)
+	}
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			return nil, err
+		}
+	}
+	return &Anonymizer{
+		key:       key,
+		precision: precision,
+		mapping:   map[uuid.UUID]uuid.UUID{},
+		used:      map[uuid.UUID]bool{},
+	}, nil
+}
+
+// Anonymize returns the anonymized GlobalId of an IFC GUID.
+// GlobalIds that aren't time-based are returned unchanged.
+func (a *Anonymizer) Anonymize(ifcGuid string) (string, error) {
+	u, err := ToUuid(ifcGuid)
+	if err != nil {
+		return "", err
+	}
+	if !isTimeBased(u) {
+		return ifcGuid, nil
+	}
+	if anonymized, ok := a.mapping[u]; ok {
+		return FromUuid(anonymized)
+	}
+
+	result := u
+	// The node id, with the multicast bit set to mark it as random, as RFC 9562 requires.
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write(u[10:16])
+	copy(result[10:16], mac.Sum(nil))
+	result[10] |= 1
+
+	if a.precision > 0 {
+		// The timestamp counts 100 ns intervals since 1582-10-15.
+		ticks := uint64(u.Time())
+		unit := uint64(a.precision / 100)
+		if unit == 0 {
+			unit = 1
+		}
+		setUuidTimestamp(&result, ticks-ticks%unit)
+	}
+	// Increment the 14-bit clock sequence until the result is unique.
+	for i := 0; a.used[result] && i < 1<<14; i++ {
+		seq := (int(result[8]&0x3f)<<8 | int(result[9])) + 1
+		result[8] = result[8]&0xc0 | byte(seq>>8)&0x3f
+		result[9] = byte(seq)
+	}
+	if a.used[result] {
+		return "", fmt.Errorf("can't anonymize %s: too many GlobalIds with the same time and node", ifcGuid)
+	}
+	a.used[result] = true
+	a.mapping[u] = result
+	return FromUuid(result)
+}
+
+// isTimeBased reports whether u is a time-based RFC 4122 UUID.
+func isTimeBased(u uuid.UUID) bool {
+	return u.Version() == 1 && u.Variant() == uuid.RFC4122
+}
+
+// setUuidTimestamp sets the 60-bit timestamp of a time-based UUID, keeping its version.
+func setUuidTimestamp(u *uuid.UUID, ticks uint64) {
+	low := uint32(ticks)
+	mid := uint16(ticks >> 32)
+	high := uint16(ticks>>48) & 0x0fff
+	u[0], u[1], u[2], u[3] = byte(low>>24), byte(low>>16), byte(low>>8), byte(low)
+	u[4], u[5] = byte(mid>>8), byte(mid)
+	u[6], u[7] = 0x10|byte(high>>8), byte(high)
+}
+
+// uuidTime returns the creation time of a time-based UUID.
+func uuidTime(u uuid.UUID) time.Time {
+	return time.Unix(u.Time().UnixTime()).UTC()
+}
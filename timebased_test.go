@@ -0,0 +1,108 @@
+package ifcguid
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// _rfcExample is the UUIDv1 example of RFC 9562, created at 2022-02-22 19:22:22 UTC.
+const _rfcExample = "c232ab00-9414-11ec-b3c8-9f6bdeced846"
+
+func Test_InspectTimeBased(t *testing.T) {
+	ifcGuid, err := FromUuidString(_rfcExample)
+	assert.NoError(t, err)
+
+	info, ok, err := InspectTimeBased(ifcGuid)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, ifcGuid, info.GlobalId)
+	assert.Equal(t, time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC), info.Time)
+	assert.Equal(t, 0x33c8, info.ClockSequence)
+	assert.Equal(t, "9f:6b:de:ce:d8:46", info.Node.String())
+	assert.True(t, info.Random)
+
+	random, err := New()
+	assert.NoError(t, err)
+	_, ok, err = InspectTimeBased(random)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	_, _, err = InspectTimeBased("invalid")
+	assert.Error(t, err)
+}
+
+func Test_Anonymizer(t *testing.T) {
+	a, err := NewAnonymizer([]byte("key"), 0)
+	assert.NoError(t, err)
+
+	ifcGuid, err := FromUuidString(_rfcExample)
+	assert.NoError(t, err)
+	anonymized, err := a.Anonymize(ifcGuid)
+	assert.NoError(t, err)
+	assert.NotEqual(t, ifcGuid, anonymized)
+
+	info, ok, err := InspectTimeBased(anonymized)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, info.Random)
+	assert.NotEqual(t, "9f:6b:de:ce:d8:46", info.Node.String())
+	assert.Equal(t, time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC), info.Time, "the time is kept")
+	assert.Equal(t, 0x33c8, info.ClockSequence)
+
+	again, err := a.Anonymize(ifcGuid)
+	assert.NoError(t, err)
+	assert.Equal(t, anonymized, again)
+
+	// The same key gives the same node, another key another node.
+	same, err := NewAnonymizer([]byte("key"), 0)
+	assert.NoError(t, err)
+	sameAnonymized, err := same.Anonymize(ifcGuid)
+	assert.NoError(t, err)
+	assert.Equal(t, anonymized, sameAnonymized)
+	other, err := NewAnonymizer(nil, 0)
+	assert.NoError(t, err)
+	otherAnonymized, err := other.Anonymize(ifcGuid)
+	assert.NoError(t, err)
+	assert.NotEqual(t, anonymized, otherAnonymized)
+
+	// GlobalIds that aren't time-based are kept.
+	random, err := New()
+	assert.NoError(t, err)
+	kept, err := a.Anonymize(random)
+	assert.NoError(t, err)
+	assert.Equal(t, random, kept)
+
+	_, err = a.Anonymize("invalid")
+	assert.Error(t, err)
+	_, err = NewAnonymizer(nil, -time.Second)
+	assert.Error(t, err)
+}
+
+func Test_Anonymizer_precision(t *testing.T) {
+	a, err := NewAnonymizer([]byte("key"), time.Hour)
+	assert.NoError(t, err)
+
+	// Two UUIDs of the same node and clock sequence, created 25.6 µs apart.
+	first := uuid.MustParse(_rfcExample)
+	second := first
+	second[3]++
+	nodes := map[string]bool{}
+	seqs := map[int]bool{}
+	for _, u := range []uuid.UUID{first, second} {
+		ifcGuid, err := FromUuid(u)
+		assert.NoError(t, err)
+		anonymized, err := a.Anonymize(ifcGuid)
+		assert.NoError(t, err)
+		info, ok, err := InspectTimeBased(anonymized)
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.Equal(t, time.Date(2022, 2, 22, 19, 0, 0, 0, time.UTC), info.Time)
+		nodes[info.Node.String()] = true
+		seqs[info.ClockSequence] = true
+	}
+	assert.Len(t, nodes, 1, "the node is replaced consistently")
+	assert.Len(t, seqs, 2, "the clock sequence keeps the GlobalIds unique")
+}
@@ -0,0 +1,98 @@
+package xlsx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// _maxHeaderRow is the number of rows searched for the header row.
+// Schedules exported from authoring tools often start with a title row.
+const _maxHeaderRow = 10
+
+// Converter converts a single cell value, e.g. ifcguid.FromRevitUniqueId.
+type Converter func(value string) (string, error)
+
+// CellError is a cell whose value couldn't be converted.
+type CellError struct {
+	Sheet string
+	// Cell is the A1-style name of the cell.
+	Cell  string
+	Value string
+	Err   error
+}
+
+// Error implements the error interface.
+func (e CellError) Error() string {
+	return fmt.Sprintf("%s!%s: %q: %v", e.Sheet, e.Cell, e.Value, e.Err)
+}
+
+// ConvertColumn converts the values of the column named from, and writes the results to the column named to.
+//
+// Columns are identified by their header, which is the first of the first 10 rows that contains from.
+// If from and to are equal, the column is converted in place.
+// If there is no column named to, it is added after the last column of the header row.
+// Empty cells are skipped. Cells that can't be converted are left unchanged and returned as CellErrors.
+//
+// For example, to add IFC GUIDs to a Revit schedule:
+//
+//	n, errs, err := sheet.ConvertColumn("UniqueId", "IfcGUID", ifcguid.FromRevitUniqueId)
+func (s *Sheet) ConvertColumn(from, to string, convert Converter) (int, []CellError, error) {
+	headerRow, fromCol, ok := s.findColumn(from)
+	if !ok {
+		return 0, nil, fmt.Errorf("sheet %q: column %q not found", s.Name, from)
+	}
+	toCol := s.column(headerRow, to)
+	if toCol < 0 {
+		header := s.row(headerRow)
+		toCol = header.cells[len(header.cells)-1].col + 1
+		if err := s.SetCell(headerRow, toCol, to); err != nil {
+			return 0, nil, err
+		}
+	}
+	converted := 0
+	var cellErrors []CellError
+	for _, r := range s.rows {
+		if r.index <= headerRow {
+			continue
+		}
+		c := r.cell(fromCol)
+		if c == nil || strings.TrimSpace(c.value) == "" {
+			continue
+		}
+		result, err := convert(strings.TrimSpace(c.value))
+		if err != nil {
+			cellErrors = append(cellErrors, CellError{Sheet: s.Name, Cell: CellName(r.index, fromCol), Value: c.value, Err: err})
+			continue
+		}
+		if err := s.SetCell(r.index, toCol, result); err != nil {
+			return converted, cellErrors, err
+		}
+		converted++
+	}
+	return converted, cellErrors, nil
+}
+
+// findColumn returns the 0-based header row and column of the column with the given name.
+func (s *Sheet) findColumn(name string) (int, int, bool) {
+	for _, r := range s.rows {
+		if r.index >= _maxHeaderRow {
+			break
+		}
+		if col := s.column(r.index, name); col >= 0 {
+			return r.index, col, true
+		}
+	}
+	return 0, 0, false
+}
+
+// column returns the 0-based column of the cell with the given value in the given row, or -1.
+func (s *Sheet) column(rowIndex int, name string) int {
+	if r := s.row(rowIndex); r != nil {
+		for _, c := range r.cells {
+			if strings.TrimSpace(c.value) == name {
+				return c.col
+			}
+		}
+	}
+	return -1
+}
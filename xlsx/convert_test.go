@@ -0,0 +1,89 @@
+package xlsx
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/woweh/ifcguid"
+)
+
+func Test_ConvertColumn(t *testing.T) {
+	data := testXlsx(t)
+	wb, err := Read(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	walls := wb.Sheet("Walls")
+
+	n, cellErrors, err := walls.ConvertColumn("UniqueId", "IfcGUID", ifcguid.FromRevitUniqueId)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Empty(t, cellErrors)
+
+	n, cellErrors, err = walls.ConvertColumn("IfcGUID", "UUID", ifcguid.ToUuidString)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Empty(t, cellErrors)
+
+	// Convert in place; "Mark" values aren't IFC GUIDs.
+	n, cellErrors, err = walls.ConvertColumn("Mark", "Mark", ifcguid.ToUuidString)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, n)
+	if assert.Len(t, cellErrors, 2) {
+		assert.Equal(t, "C2", cellErrors[0].Cell)
+		assert.Contains(t, cellErrors[0].Error(), `Walls!C2: "1.5E+10"`)
+	}
+
+	_, _, err = walls.ConvertColumn("ElementId", "IfcGUID", ifcguid.FromIntString)
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	assert.NoError(t, wb.Write(&buf))
+	reopened, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"UniqueId", "IfcGUID", "Mark", "UUID"},
+		{"8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e", "2DWKyvjkf7PffFYiFUDNsy", "1.5E+10", "8d814f39-b6ea-4766-9a4f-8ac3de357dbc"},
+		{"00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd", "00lQsbQXP4OA7Ejivjtxsz", "W1", "00bdada5-6a16-4460-a1ce-b6ce6ddfbdbd"},
+		nil,
+		nil,
+	}, reopened.Sheet("Walls").Rows())
+
+	// The other sheet and the shared strings are unchanged.
+	before := readParts(t, data)
+	after := readParts(t, buf.Bytes())
+	assert.Equal(t, before["xl/sharedStrings.xml"], after["xl/sharedStrings.xml"])
+	assert.Equal(t, before["xl/worksheets/sheet2.xml"], after["xl/worksheets/sheet2.xml"])
+	assert.Contains(t, after["xl/worksheets/sheet1.xml"], `<c r="C1" s="1" t="s"><v>3</v></c><c r="D1" t="inlineStr"><is><t>UUID</t></is></c></row>`)
+}
+
+func Test_ConvertColumn_with_title_row(t *testing.T) {
+	// Schedules exported from authoring tools often start with a title row above the header.
+	sheet := `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>
+<row r="1"><c r="A1" t="inlineStr"><is><t>Wall Schedule</t></is></c></row>
+<row r="2"><c r="A2" t="s"><v>3</v></c><c r="B2" t="s"><v>0</v></c></row>
+<row r="3"><c r="A3" t="inlineStr"><is><t>W1</t></is></c><c r="B3" t="s"><v>2</v></c></row>
+<row r="4"><c r="A4" t="inlineStr"><is><t>W2</t></is></c><c r="B4" t="inlineStr"><is><t>00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd</t></is></c></row>
+</sheetData></worksheet>`
+	data := testXlsxWith(t, sheet)
+	wb, err := Read(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	walls := wb.Sheet("Walls")
+
+	n, cellErrors, err := walls.ConvertColumn("UniqueId", "IfcGUID", ifcguid.FromRevitUniqueId)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, n)
+	assert.Empty(t, cellErrors)
+
+	var buf bytes.Buffer
+	assert.NoError(t, wb.Write(&buf))
+	reopened, err := Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Wall Schedule"},
+		{"Mark", "UniqueId", "IfcGUID"},
+		{"W1", "8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e", "2DWKyvjkf7PffFYiFUDNsy"},
+		{"W2", "00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd", "00lQsbQXP4OA7Ejivjtxsz"},
+	}, reopened.Sheet("Walls").Rows())
+}
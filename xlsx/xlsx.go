@@ -0,0 +1,561 @@
+// Package xlsx reads and edits Office Open XML workbooks (.xlsx) using only the standard library.
+//
+// Cell values are edited in place: only the XML of changed cells is rewritten,
+// so formatting, other sheets, the shared strings table and all other parts of the workbook are preserved.
+// Changed cells are written as inline strings and keep their style.
+//
+// Usage:
+//
+//	wb, err := xlsx.Open("schedule.xlsx")
+//	sheet := wb.Sheet("Walls")
+//	rows := sheet.Rows()
+//	err = sheet.SetCell(1, 2, "2DWKyvjkf7PffFYiFUDNsy")
+//	err = wb.Save("schedule_ifc.xlsx")
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	_workbookPath      = "xl/workbook.xml"
+	_workbookRelsPath  = "xl/_rels/workbook.xml.rels"
+	_sharedStringsPath = "xl/sharedStrings.xml"
+)
+
+// Workbook is an .xlsx file loaded into memory.
+type Workbook struct {
+	files  []*file
+	sheets []*Sheet
+	shared []string
+}
+
+// file is a part (zip entry) of the workbook.
+type file struct {
+	header zip.FileHeader
+	data   []byte
+}
+
+// Sheet is a worksheet of a workbook.
+type Sheet struct {
+	// Name is the name of the sheet as shown in the tab.
+	Name   string
+	file   *file
+	shared []string
+	rows   []*row
+}
+
+// row is a row element of a worksheet, with the byte offsets of its parts.
+type row struct {
+	index    int // 0-based
+	start    int // offset of '<row'
+	startEnd int // offset after the start tag
+	end      int // offset of '</row>', or -1 for an empty element
+	after    int // offset after the row element
+	cells    []*cell
+}
+
+// cell is a c element of a worksheet, with the byte offsets of its parts.
+type cell struct {
+	col     int // 0-based
+	start   int // offset of '<c'
+	end     int // offset after the cell element
+	style   string
+	value   string
+	changed bool
+}
+
+// Open reads the workbook at the given path.
+func Open(name string) (*Workbook, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return Read(f, info.Size())
+}
+
+// Read reads a workbook from r.
+func Read(r io.ReaderAt, size int64) (*Workbook, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, err
+	}
+	wb := &Workbook{}
+	byName := map[string]*file{}
+	for _, zf := range zr.File {
+		rc, err := zf.Open()
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		f := &file{header: zf.FileHeader, data: data}
+		wb.files = append(wb.files, f)
+		byName[zf.Name] = f
+	}
+	if f, ok := byName[_sharedStringsPath]; ok {
+		if wb.shared, err = parseSharedStrings(f.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := wb.loadSheets(byName); err != nil {
+		return nil, err
+	}
+	return wb, nil
+}
+
+// Sheets returns the worksheets in workbook order.
+func (wb *Workbook) Sheets() []*Sheet {
+	return wb.sheets
+}
+
+// Sheet returns the worksheet with the given name, or nil if there is none.
+func (wb *Workbook) Sheet(name string) *Sheet {
+	for _, s := range wb.sheets {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+// Write writes the workbook to w. Parts without changes are written byte for byte.
+func (wb *Workbook) Write(w io.Writer) error {
+	zw := zip.NewWriter(w)
+	for _, s := range wb.sheets {
+		if err := s.apply(); err != nil {
+			return err
+		}
+	}
+	for _, f := range wb.files {
+		header := f.header
+		fw, err := zw.CreateHeader(&header)
+		if err != nil {
+			return err
+		}
+		if _, err := fw.Write(f.data); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// Save writes the workbook to the file with the given name.
+func (wb *Workbook) Save(name string) error {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	err = wb.Write(f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Rows returns the cell values of the sheet as a dense grid, indexed by 0-based row and column.
+// Numbers are returned as written in the file, e.g. "1.5E+10".
+func (s *Sheet) Rows() [][]string {
+	if len(s.rows) == 0 {
+		return nil
+	}
+	result := make([][]string, s.rows[len(s.rows)-1].index+1)
+	for _, r := range s.rows {
+		if len(r.cells) == 0 {
+			continue
+		}
+		values := make([]string, r.cells[len(r.cells)-1].col+1)
+		for _, c := range r.cells {
+			values[c.col] = c.value
+		}
+		result[r.index] = values
+	}
+	return result
+}
+
+// Cell returns the value of the cell at the 0-based row and column, or an empty string if there is none.
+func (s *Sheet) Cell(rowIndex, col int) string {
+	if r := s.row(rowIndex); r != nil {
+		if c := r.cell(col); c != nil {
+			return c.value
+		}
+	}
+	return ""
+}
+
+// SetCell sets the value of the cell at the 0-based row and column to a string.
+// The cell keeps its style; a missing cell is created in the existing row.
+// It returns an error if the row doesn't exist.
+func (s *Sheet) SetCell(rowIndex, col int, value string) error {
+	r := s.row(rowIndex)
+	if r == nil {
+		return fmt.Errorf("sheet %q: row %d doesn't exist", s.Name, rowIndex+1)
+	}
+	c := r.cell(col)
+	if c == nil {
+		c = &cell{col: col, start: -1}
+		i := sort.Search(len(r.cells), func(i int) bool { return r.cells[i].col > col })
+		r.cells = append(r.cells, nil)
+		copy(r.cells[i+1:], r.cells[i:])
+		r.cells[i] = c
+	}
+	if c.value != value || c.start < 0 {
+		c.value = value
+		c.changed = true
+	}
+	return nil
+}
+
+// row returns the row with the given 0-based index.
+func (s *Sheet) row(index int) *row {
+	i := sort.Search(len(s.rows), func(i int) bool { return s.rows[i].index >= index })
+	if i < len(s.rows) && s.rows[i].index == index {
+		return s.rows[i]
+	}
+	return nil
+}
+
+// cell returns the cell with the given 0-based column.
+func (r *row) cell(col int) *cell {
+	for _, c := range r.cells {
+		if c.col == col {
+			return c
+		}
+	}
+	return nil
+}
+
+// apply rewrites the sheet XML with the changed cells, and re-parses it so that all offsets are up to date.
+func (s *Sheet) apply() error {
+	data := s.file.data
+	var buf bytes.Buffer
+	pos := 0
+	changed := false
+	for _, r := range s.rows {
+		if !r.changed() {
+			continue
+		}
+		changed = true
+		if r.end < 0 {
+			// An empty <row .../> element: rewrite it with an end tag.
+			buf.Write(data[pos:r.start])
+			buf.Write(bytes.TrimSuffix(bytes.TrimRight(data[r.start:r.after], " \t\r\n>"), []byte("/")))
+			buf.WriteString(">")
+			for _, c := range r.cells {
+				buf.WriteString(c.xml(r.index))
+			}
+			buf.WriteString("</row>")
+			pos = r.after
+			continue
+		}
+		// New cells are inserted after the preceding existing cell, to keep the cells ordered by column.
+		insertAt := r.startEnd
+		for _, c := range r.cells {
+			switch {
+			case c.start < 0:
+				buf.Write(data[pos:insertAt])
+				buf.WriteString(c.xml(r.index))
+				pos = insertAt
+			case c.changed:
+				buf.Write(data[pos:c.start])
+				buf.WriteString(c.xml(r.index))
+				pos = c.end
+				insertAt = c.end
+			default:
+				insertAt = c.end
+			}
+		}
+	}
+	if !changed {
+		return nil
+	}
+	buf.Write(data[pos:])
+	rows, err := parseSheet(buf.Bytes(), s.shared)
+	if err != nil {
+		return fmt.Errorf("sheet %q: %w", s.Name, err)
+	}
+	s.file.data = buf.Bytes()
+	s.rows = rows
+	return nil
+}
+
+// changed reports whether any cell of the row was changed.
+func (r *row) changed() bool {
+	for _, c := range r.cells {
+		if c.changed {
+			return true
+		}
+	}
+	return false
+}
+
+// xml returns the XML of a changed cell as an inline string.
+func (c *cell) xml(rowIndex int) string {
+	var sb strings.Builder
+	sb.WriteString(`<c r="`)
+	sb.WriteString(CellName(rowIndex, c.col))
+	sb.WriteString(`"`)
+	if c.style != "" {
+		sb.WriteString(` s="`)
+		sb.WriteString(c.style)
+		sb.WriteString(`"`)
+	}
+	sb.WriteString(` t="inlineStr"><is><t`)
+	if strings.TrimSpace(c.value) != c.value {
+		sb.WriteString(` xml:space="preserve"`)
+	}
+	sb.WriteString(`>`)
+	_ = xml.EscapeText(&sb, []byte(c.value))
+	sb.WriteString(`</t></is></c>`)
+	return sb.String()
+}
+
+// CellName returns the A1-style name of the cell at the 0-based row and column, e.g. CellName(0, 27) = "AB1".
+func CellName(rowIndex, col int) string {
+	return ColumnName(col) + strconv.Itoa(rowIndex+1)
+}
+
+// ColumnName returns the letters of the 0-based column, e.g. ColumnName(27) = "AB".
+func ColumnName(col int) string {
+	var letters []byte
+	for col >= 0 {
+		letters = append([]byte{byte('A' + col%26)}, letters...)
+		col = col/26 - 1
+	}
+	return string(letters)
+}
+
+// parseCellName returns the 0-based row and column of an A1-style cell name.
+func parseCellName(name string) (int, int, error) {
+	col := 0
+	i := 0
+	for ; i < len(name) && name[i] >= 'A' && name[i] <= 'Z'; i++ {
+		col = col*26 + int(name[i]-'A'+1)
+	}
+	rowNumber, err := strconv.Atoi(name[i:])
+	if i == 0 || err != nil || rowNumber < 1 {
+		return 0, 0, fmt.Errorf("invalid cell name: %q", name)
+	}
+	return rowNumber - 1, col - 1, nil
+}
+
+// loadSheets reads the sheet list from the workbook and parses every worksheet.
+func (wb *Workbook) loadSheets(byName map[string]*file) error {
+	wbFile, ok := byName[_workbookPath]
+	if !ok {
+		return fmt.Errorf("not a workbook: %s is missing", _workbookPath)
+	}
+	var workbook struct {
+		Sheets []struct {
+			Name string `xml:"name,attr"`
+			Id   string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	if err := xml.Unmarshal(wbFile.data, &workbook); err != nil {
+		return fmt.Errorf("error parsing %s: %w", _workbookPath, err)
+	}
+	targets := map[string]string{}
+	if relsFile, ok := byName[_workbookRelsPath]; ok {
+		var rels struct {
+			Relationships []struct {
+				Id     string `xml:"Id,attr"`
+				Target string `xml:"Target,attr"`
+			} `xml:"Relationship"`
+		}
+		if err := xml.Unmarshal(relsFile.data, &rels); err != nil {
+			return fmt.Errorf("error parsing %s: %w", _workbookRelsPath, err)
+		}
+		for _, r := range rels.Relationships {
+			targets[r.Id] = r.Target
+		}
+	}
+	for _, ws := range workbook.Sheets {
+		target, ok := targets[ws.Id]
+		if !ok {
+			return fmt.Errorf("sheet %q: relationship %q not found", ws.Name, ws.Id)
+		}
+		if strings.HasPrefix(target, "/") {
+			target = target[1:]
+		} else {
+			target = path.Join("xl", target)
+		}
+		f, ok := byName[target]
+		if !ok {
+			// Chart sheets and dialog sheets have no cells; skip missing parts.
+			continue
+		}
+		rows, err := parseSheet(f.data, wb.shared)
+		if err != nil {
+			return fmt.Errorf("sheet %q: %w", ws.Name, err)
+		}
+		wb.sheets = append(wb.sheets, &Sheet{Name: ws.Name, file: f, shared: wb.shared, rows: rows})
+	}
+	return nil
+}
+
+// parseSharedStrings parses the shared strings table.
+func parseSharedStrings(data []byte) ([]string, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var result []string
+	var sb strings.Builder
+	inText, inPhonetic := false, false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing %s: %w", _sharedStringsPath, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "si":
+				sb.Reset()
+			case "t":
+				inText = true
+			case "rPh":
+				inPhonetic = true
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "si":
+				result = append(result, sb.String())
+			case "t":
+				inText = false
+			case "rPh":
+				inPhonetic = false
+			}
+		case xml.CharData:
+			if inText && !inPhonetic {
+				sb.Write(t)
+			}
+		}
+	}
+}
+
+// parseSheet parses the rows and cells of a worksheet, recording their byte offsets.
+func parseSheet(data []byte, shared []string) ([]*row, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	var rows []*row
+	var currentRow *row
+	var currentCell *cell
+	var cellType string
+	var text strings.Builder
+	inValue, inPhonetic := false, false
+	for {
+		start := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "row":
+				index := 0
+				if len(rows) > 0 {
+					index = rows[len(rows)-1].index + 1
+				}
+				if r := attr(t, "r"); r != "" {
+					n, err := strconv.Atoi(r)
+					if err != nil || n < 1 {
+						return nil, fmt.Errorf("invalid row number: %q", r)
+					}
+					index = n - 1
+				}
+				currentRow = &row{index: index, start: start, startEnd: int(decoder.InputOffset())}
+				rows = append(rows, currentRow)
+			case "c":
+				if currentRow == nil {
+					continue
+				}
+				col := 0
+				if n := len(currentRow.cells); n > 0 {
+					col = currentRow.cells[n-1].col + 1
+				}
+				if r := attr(t, "r"); r != "" {
+					_, c, err := parseCellName(r)
+					if err != nil {
+						return nil, err
+					}
+					col = c
+				}
+				currentCell = &cell{col: col, start: start, style: attr(t, "s")}
+				cellType = attr(t, "t")
+				text.Reset()
+			case "v", "t":
+				inValue = currentCell != nil
+			case "rPh":
+				inPhonetic = true
+			}
+		case xml.CharData:
+			if inValue && !inPhonetic {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "row":
+				if currentRow == nil {
+					continue
+				}
+				currentRow.after = int(decoder.InputOffset())
+				if currentRow.after == currentRow.startEnd {
+					currentRow.end = -1
+				} else {
+					currentRow.end = start
+				}
+				currentRow = nil
+			case "c":
+				if currentCell == nil {
+					continue
+				}
+				currentCell.end = int(decoder.InputOffset())
+				currentCell.value = text.String()
+				if cellType == "s" {
+					i, err := strconv.Atoi(currentCell.value)
+					if err != nil || i < 0 || i >= len(shared) {
+						return nil, fmt.Errorf("invalid shared string index: %q", currentCell.value)
+					}
+					currentCell.value = shared[i]
+				}
+				currentRow.cells = append(currentRow.cells, currentCell)
+				currentCell = nil
+			case "v", "t":
+				inValue = false
+			case "rPh":
+				inPhonetic = false
+			}
+		}
+	}
+}
+
+// attr returns the value of the attribute with the given local name.
+func attr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name && a.Name.Space == "" {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,179 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testWorkbook = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">
+<sheets><sheet name="Walls" sheetId="1" r:id="rId1"/><sheet name="Notes" sheetId="2" r:id="rId2"/></sheets>
+</workbook>`
+	testWorkbookRels = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">
+<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>
+<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="/xl/worksheets/sheet2.xml"/>
+<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>
+</Relationships>`
+	testSharedStrings = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" count="4" uniqueCount="4">
+<si><t>UniqueId</t></si><si><t>IfcGUID</t></si>
+<si><r><rPr><b/></rPr><t>8d814f39-b6ea-4766-9a4f-</t></r><r><t>8ac3de3501b2-00007c0e</t></r></si>
+<si><t>Mark</t><rPh sb="0" eb="1"><t>phonetic</t></rPh></si>
+</sst>`
+	testSheet1 = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><dimension ref="A1:C4"/><cols><col min="1" max="1" width="45" customWidth="1"/></cols><sheetData>
+<row r="1" spans="1:3"><c r="A1" s="1" t="s"><v>0</v></c><c r="B1" s="1" t="s"><v>1</v></c><c r="C1" s="1" t="s"><v>3</v></c></row>
+<row r="2" spans="1:3"><c r="A2" t="s"><v>2</v></c><c r="C2"><v>1.5E+10</v></c></row>
+<row r="3" spans="1:3"><c r="A3" t="inlineStr"><is><t>00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd</t></is></c><c r="B3" s="2"/><c r="C3" t="str"><f>"W"&amp;1</f><v>W1</v></c></row>
+<row r="5"/>
+</sheetData><mergeCells count="1"><mergeCell ref="D1:E1"/></mergeCells></worksheet>`
+	testSheet2 = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData><row><c><v>42</v></c><c t="b"><v>1</v></c></row></sheetData></worksheet>`
+	testStyles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"/>`
+)
+
+// testXlsx returns a small workbook with two sheets, shared strings, styles and a formula.
+func testXlsx(t *testing.T) []byte {
+	return testXlsxWith(t, testSheet1)
+}
+
+// testXlsxWith returns the workbook of testXlsx, with sheet1 as the content of the "Walls" sheet.
+func testXlsxWith(t *testing.T, sheet1 string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range []struct{ name, content string }{
+		{"[Content_Types].xml", `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`},
+		{"xl/workbook.xml", testWorkbook},
+		{"xl/_rels/workbook.xml.rels", testWorkbookRels},
+		{"xl/sharedStrings.xml", testSharedStrings},
+		{"xl/styles.xml", testStyles},
+		{"xl/worksheets/sheet1.xml", sheet1},
+		{"xl/worksheets/sheet2.xml", testSheet2},
+	} {
+		w, err := zw.Create(f.name)
+		assert.NoError(t, err)
+		_, err = io.WriteString(w, f.content)
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// readParts returns the content of every part of a workbook.
+func readParts(t *testing.T, data []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		assert.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		assert.NoError(t, err)
+		rc.Close()
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func Test_Read(t *testing.T) {
+	data := testXlsx(t)
+	wb, err := Read(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	if !assert.Len(t, wb.Sheets(), 2) {
+		return
+	}
+	assert.Nil(t, wb.Sheet("Missing"))
+
+	walls := wb.Sheet("Walls")
+	assert.Equal(t, [][]string{
+		{"UniqueId", "IfcGUID", "Mark"},
+		{"8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e", "", "1.5E+10"},
+		{"00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd", "", "W1"},
+		nil,
+		nil,
+	}, walls.Rows())
+	assert.Equal(t, "Mark", walls.Cell(0, 2))
+	assert.Equal(t, "", walls.Cell(10, 0))
+
+	notes := wb.Sheet("Notes")
+	assert.Equal(t, [][]string{{"42", "1"}}, notes.Rows())
+}
+
+func Test_SetCell_and_Write(t *testing.T) {
+	data := testXlsx(t)
+	wb, err := Read(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	walls := wb.Sheet("Walls")
+
+	assert.NoError(t, walls.SetCell(1, 1, "2DWKyvjkf7PffFYiFUDNsy")) // missing cell
+	assert.NoError(t, walls.SetCell(2, 1, "00lQsbQXP4OA7Ejivjtxsz")) // existing, styled, empty cell
+	assert.NoError(t, walls.SetCell(1, 2, "1.5E+10 & <more>"))       // number cell
+	assert.NoError(t, walls.SetCell(4, 0, " padded "))               // empty row element
+	assert.Error(t, walls.SetCell(3, 0, "no such row"))
+
+	name := filepath.Join(t.TempDir(), "out.xlsx")
+	assert.NoError(t, wb.Save(name))
+
+	reopened, err := Open(name)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"UniqueId", "IfcGUID", "Mark"},
+		{"8d814f39-b6ea-4766-9a4f-8ac3de3501b2-00007c0e", "2DWKyvjkf7PffFYiFUDNsy", "1.5E+10 & <more>"},
+		{"00bdada5-6a16-4460-a1ce-b6ce6dc1cf00-001e72bd", "00lQsbQXP4OA7Ejivjtxsz", "W1"},
+		nil,
+		{" padded "},
+	}, reopened.Sheet("Walls").Rows())
+
+	// Everything but the changed cells is preserved byte for byte.
+	var buf bytes.Buffer
+	assert.NoError(t, reopened.Write(&buf))
+	before := readParts(t, data)
+	after := readParts(t, buf.Bytes())
+	assert.Len(t, after, len(before))
+	for name, content := range before {
+		if name != "xl/worksheets/sheet1.xml" {
+			assert.Equal(t, content, after[name], name)
+		}
+	}
+	sheet := after["xl/worksheets/sheet1.xml"]
+	assert.Contains(t, sheet, `<c r="A2" t="s"><v>2</v></c><c r="B2" t="inlineStr"><is><t>2DWKyvjkf7PffFYiFUDNsy</t></is></c><c r="C2" t="inlineStr"><is><t>1.5E+10 &amp; &lt;more&gt;</t></is></c></row>`)
+	assert.Contains(t, sheet, `<c r="B3" s="2" t="inlineStr"><is><t>00lQsbQXP4OA7Ejivjtxsz</t></is></c><c r="C3" t="str"><f>"W"&amp;1</f><v>W1</v></c>`)
+	assert.Contains(t, sheet, `<row r="5"><c r="A5" t="inlineStr"><is><t xml:space="preserve"> padded </t></is></c></row>`)
+	assert.Contains(t, sheet, `<cols><col min="1" max="1" width="45" customWidth="1"/></cols>`)
+	assert.Contains(t, sheet, `<mergeCells count="1"><mergeCell ref="D1:E1"/></mergeCells>`)
+}
+
+func Test_CellName(t *testing.T) {
+	assert.Equal(t, "A1", CellName(0, 0))
+	assert.Equal(t, "Z10", CellName(9, 25))
+	assert.Equal(t, "AB3", CellName(2, 27))
+	assert.Equal(t, "ZZ1", CellName(0, 701))
+	assert.Equal(t, "AAA1", CellName(0, 702))
+
+	row, col, err := parseCellName("AB3")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, row)
+	assert.Equal(t, 27, col)
+	_, _, err = parseCellName("3A")
+	assert.Error(t, err)
+}
+
+func Test_Read_with_invalid_data(t *testing.T) {
+	_, err := Read(bytes.NewReader([]byte("not a zip")), 9)
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, err = zw.Create("xl/styles.xml")
+	assert.NoError(t, err)
+	assert.NoError(t, zw.Close())
+	_, err = Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.ErrorContains(t, err, "not a workbook")
+}